@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/k0wl0n/gctx/pkg/manager"
 	"github.com/spf13/cobra"
 )
 
@@ -17,20 +16,21 @@ var activeCmd = &cobra.Command{
   gctx active my-account`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		m, err := manager.New()
+		m, err := newManager(cmd)
 		if err != nil {
 			return err
 		}
 
 		// If an argument is provided, behave like switch
 		if len(args) > 0 {
-			return m.SwitchAccount(args[0])
+			return switchTo(cmd.Context(), m, args[0])
 		}
 
 		// Otherwise, show active account
-		active, err := m.GetActiveAccount()
-		if err != nil {
-			return err
+		active := m.ActiveAccount()
+		if active == "" {
+			fmt.Println("No active account")
+			return nil
 		}
 
 		fmt.Printf("Active account: %s\n", active)
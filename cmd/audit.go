@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/audit"
+	"github.com/spf13/cobra"
+)
+
+var (
+	auditSince   string
+	auditAccount string
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the audit log of credential and account operations",
+	Example: `  # Show everything recorded in the last 24 hours
+  gctx audit --since 24h
+
+  # Show operations on one account since a date
+  gctx audit --account work --since 2025-01-31`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since, err := parseSince(auditSince)
+		if err != nil {
+			return err
+		}
+
+		events, err := audit.Load(since)
+		if err != nil {
+			return err
+		}
+
+		shown := 0
+		for _, e := range events {
+			if auditAccount != "" && e.Account != auditAccount {
+				continue
+			}
+			shown++
+
+			line := fmt.Sprintf("  %s  %-6s  %s", e.Time.Local().Format("2006-01-02 15:04:05"),
+				e.Action, e.Account)
+			if e.Project != "" {
+				line += fmt.Sprintf(" (%s)", e.Project)
+			}
+			if e.Fingerprint != "" {
+				line += "  " + e.Fingerprint
+			}
+			line += fmt.Sprintf("  %s[%d]", e.Hostname, e.PID)
+			if e.Command != "" {
+				line += fmt.Sprintf("  via %s", e.Command)
+			}
+			if len(e.Args) > 0 {
+				line += fmt.Sprintf(": gcloud %s", strings.Join(e.Args, " "))
+			}
+			if e.Error != "" {
+				line += fmt.Sprintf("  error: %s", e.Error)
+			}
+			fmt.Println(line)
+		}
+
+		if shown == 0 {
+			fmt.Println("No audit events")
+		}
+		return nil
+	},
+}
+
+// parseSince accepts a duration back from now (e.g. 24h) or a date or
+// timestamp (2006-01-02, RFC 3339)
+func parseSince(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since '%s': expected a duration (24h), a date (2006-01-02) or an RFC 3339 time", value)
+}
+
+func init() {
+	auditCmd.Flags().StringVar(&auditSince, "since", "",
+		"Only show events after a duration ago (e.g. 24h) or a date (e.g. 2025-01-31)")
+	auditCmd.Flags().StringVar(&auditAccount, "account", "",
+		"Only show events for this account")
+}
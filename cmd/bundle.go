@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/k0wl0n/gctx/pkg/bundle"
+	"github.com/k0wl0n/gctx/pkg/manager"
+	"github.com/k0wl0n/gctx/pkg/selector"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// bundlePassphraseEnv provides the bundle passphrase non-interactively
+const bundlePassphraseEnv = "GCTX_BUNDLE_PASSPHRASE"
+
+var (
+	exportAccounts   []string
+	exportSelector   string
+	exportOut        string
+	importOnConflict string
+	importWithHooks  bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export accounts and their credentials to an encrypted bundle",
+	Long: `Package accounts (all of them by default) and their saved ADC credentials
+into a single file encrypted with a passphrase, to restore them on another
+machine with 'gctx import-bundle'.
+
+The passphrase is prompted for, or read from GCTX_BUNDLE_PASSPHRASE. The
+bundle contains refresh tokens: keep it as safe as the passphrase.`,
+	Example: `  # Export every account
+  gctx export --out gctx.bundle
+
+  # Export some accounts
+  gctx export --accounts work,personal --out gctx.bundle
+  gctx export --selector env=dev --out dev.bundle`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		names := exportAccounts
+		if exportSelector != "" {
+			sel, err := selector.Parse(exportSelector)
+			if err != nil {
+				return err
+			}
+			for _, acc := range m.SelectAccounts(sel) {
+				names = append(names, acc.Name)
+			}
+			if len(names) == 0 {
+				return fmt.Errorf("no accounts match selector '%s'", sel)
+			}
+		}
+
+		passphrase, err := readPassphrase(true)
+		if err != nil {
+			return err
+		}
+		b, err := m.ExportBundle(names, exportOut, passphrase)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Exported %d account(s) to %s\n", len(b.Entries), exportOut)
+		for _, entry := range b.Entries {
+			if len(entry.ADC) == 0 {
+				fmt.Printf("  %s (no saved ADC)\n", entry.Account.Name)
+				continue
+			}
+			fmt.Printf("  %s\n", entry.Account.Name)
+		}
+		return nil
+	},
+}
+
+var importBundleCmd = &cobra.Command{
+	Use:   "import-bundle <file>",
+	Short: "Import accounts from a bundle created by 'gctx export'",
+	Long: `Restore the accounts of an encrypted bundle: their settings, saved ADC
+credentials and gcloud configurations (project and properties).
+
+Accounts whose name is already taken are skipped by default; use
+--on-conflict overwrite to replace them or --on-conflict rename to import
+them as <name>-imported.
+
+Per-account hooks are shell commands, so they are only imported with
+--with-hooks. Links to kubectl contexts that do not exist on this machine
+are dropped.
+
+gcloud CLI credentials are not part of the bundle: run 'gctx login <account>'
+where the gcloud CLI itself needs them.`,
+	Example: `  # Restore accounts on a new machine
+  gctx import-bundle gctx.bundle
+
+  # Replace existing accounts of the same name
+  gctx import-bundle gctx.bundle --on-conflict overwrite`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		passphrase, err := readPassphrase(false)
+		if err != nil {
+			return err
+		}
+		b, err := bundle.Read(args[0], passphrase)
+		if err != nil {
+			return err
+		}
+
+		results, err := m.ImportBundle(cmd.Context(), b, importOnConflict, importWithHooks)
+		for _, r := range results {
+			switch r.Outcome {
+			case manager.ImportSkipped:
+				fmt.Printf("  %s: skipped (already exists)\n", r.Source)
+			case manager.ImportRenamed:
+				fmt.Printf("  %s: imported as %s\n", r.Source, r.Account.Name)
+			default:
+				fmt.Printf("  %s: %s\n", r.Source, r.Outcome)
+			}
+			if r.Account == nil {
+				continue
+			}
+			if !r.HasADC {
+				fmt.Printf("    no saved ADC, run: gctx login %s\n", r.Account.Name)
+			}
+			if r.DroppedHooks {
+				fmt.Printf("    hooks not imported (use --with-hooks after reviewing the bundle)\n")
+			}
+			if r.DroppedKubeContext != "" {
+				fmt.Printf("    kubectl context '%s' not found, link not imported\n", r.DroppedKubeContext)
+			}
+		}
+		return err
+	},
+}
+
+// readPassphrase reads the bundle passphrase from the environment or the
+// terminal, asking twice when creating a bundle
+func readPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(bundlePassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+
+	if !isTerminal(os.Stdin) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("no passphrase: set %s or use a terminal", bundlePassphraseEnv)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	passphrase, err := promptSecret("Bundle passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		if err := bundle.ValidatePassphrase(passphrase); err != nil {
+			return "", err
+		}
+		again, err := promptSecret("Repeat passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+func promptSecret(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	secret, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
+
+func init() {
+	exportCmd.Flags().StringSliceVar(&exportAccounts, "accounts", nil,
+		"Accounts to export (default all)")
+	exportCmd.Flags().StringVarP(&exportSelector, "selector", "l", "", selectorUsage)
+	exportCmd.Flags().StringVarP(&exportOut, "out", "o", "",
+		"File to write the bundle to")
+	exportCmd.MarkFlagRequired("out")
+
+	importBundleCmd.Flags().StringVar(&importOnConflict, "on-conflict", manager.ConflictSkip,
+		"What to do with accounts that already exist: "+strings.Join(manager.ConflictModes, ", "))
+	importBundleCmd.Flags().BoolVar(&importWithHooks, "with-hooks", false,
+		"Also import the accounts' hooks, which run shell commands")
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/selector"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkAll      bool
+	checkSelector string
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check [account-name]...",
+	Short: "Verify that saved credentials are still valid",
+	Long: `Verify saved credentials by refreshing an access token against the OAuth
+endpoint (override with GCTX_TOKEN_ENDPOINT or endpoints.token in the config).
+The result is recorded on each account and shown by list, info and prompt.
+
+Without arguments the active account is checked. Several accounts are checked
+concurrently. The command fails if any account needs a login.`,
+	Example: `  # Check the active account
+  gctx check
+
+  # Check every account
+  gctx check --all
+
+  # Check production accounts
+  gctx check --selector env=prod`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		names := args
+		if len(names) > 0 && (checkAll || checkSelector != "") {
+			return fmt.Errorf("pass either account names or --all/--selector, not both")
+		}
+		switch {
+		case checkAll || checkSelector != "":
+			sel, err := selector.Parse(checkSelector)
+			if err != nil {
+				return err
+			}
+			for _, acc := range m.SelectAccounts(sel) {
+				names = append(names, acc.Name)
+			}
+		case len(names) == 0:
+			active := m.ActiveAccount()
+			if active == "" {
+				return fmt.Errorf("no active account; pass an account name")
+			}
+			names = []string{active}
+		}
+
+		if len(names) == 0 {
+			fmt.Println("No accounts to check")
+			return nil
+		}
+
+		results, err := m.CheckCredentials(cmd.Context(), names)
+		if err != nil {
+			return err
+		}
+
+		needsLogin := 0
+		for _, r := range results {
+			switch r.Status {
+			case config.CredentialOK:
+				fmt.Printf("  ✓ %s\n", r.Account)
+			case config.CredentialNeedsLogin:
+				needsLogin++
+				fmt.Printf("  ✗ %s: needs login (run: gctx login %s)\n", r.Account, r.Account)
+			default:
+				fmt.Printf("  ! %s: %v\n", r.Account, r.Err)
+			}
+		}
+
+		if needsLogin > 0 {
+			// Not a usage error: keep the output scriptable
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d account(s) need login", needsLogin)
+		}
+		return nil
+	},
+}
+
+func init() {
+	checkCmd.Flags().BoolVar(&checkAll, "all", false, "Check every account")
+	checkCmd.Flags().StringVarP(&checkSelector, "selector", "l", "", selectorUsage)
+}
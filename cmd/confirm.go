@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+	"golang.org/x/term"
+)
+
+// assumeYes skips confirmation prompts (e.g. for protected accounts)
+var assumeYes bool
+
+// terminalConfirmer asks the user to type a protected account's name
+type terminalConfirmer struct {
+	in  *os.File
+	out io.Writer
+	yes bool
+}
+
+func (c terminalConfirmer) ConfirmAccount(account *config.Account) error {
+	if c.yes {
+		return nil
+	}
+
+	if !isTerminal(c.in) {
+		return fmt.Errorf("account '%s' is protected; pass --yes to confirm in non-interactive mode", account.Name)
+	}
+
+	fmt.Fprintf(c.out, "Account '%s' (%s) is protected.\n", account.Name, account.ProjectID)
+	fmt.Fprintf(c.out, "Type the account name to continue: ")
+
+	line, err := bufio.NewReader(c.in).ReadString('\n')
+	if err != nil && line == "" {
+		return fmt.Errorf("confirmation aborted")
+	}
+
+	if strings.TrimSpace(line) != account.Name {
+		return fmt.Errorf("confirmation did not match '%s', aborting", account.Name)
+	}
+	return nil
+}
+
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
@@ -1,32 +1,72 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
-	"github.com/k0wl0n/gctx/pkg/manager"
 )
 
-var autoSave bool
+var (
+	autoSave         bool
+	createProperties []string
+)
 
 var createCmd = &cobra.Command{
-	Use:   "create <account-name> <project-id>",
+	Use:   "create <account-name> [project-id]",
 	Short: "Create a new account configuration",
 	Example: `  # Create a new account and manually authenticate later
   gctx create my-account my-project-id
 
   # Create a new account and auto-start authentication
-  gctx create my-account my-project-id --auto-save`,
-	Args:  cobra.ExactArgs(2),
+  gctx create my-account my-project-id --auto-save
+
+  # Create an account with a default compute region
+  gctx create my-account my-project-id --set compute/region=europe-west1
+
+  # Authenticate first, then pick the project among those the account can access
+  gctx create my-account --auto-save`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		m, err := manager.New()
+		projectID := ""
+		if len(args) > 1 {
+			projectID = args[1]
+		} else if !autoSave {
+			return fmt.Errorf("a project id is required without --auto-save")
+		}
+
+		props, err := parseProperties(createProperties)
+		if err != nil {
+			return err
+		}
+
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		result, err := m.CreateAccount(cmd.Context(), args[0], projectID, props, autoSave)
 		if err != nil {
 			return err
 		}
+		fmt.Println()
+
+		if result.Login != nil {
+			printLoginResult(result.Login)
+			return nil
+		}
 
-		return m.CreateAccount(args[0], args[1], autoSave)
+		// Manual flow
+		fmt.Println("Now run the following commands:")
+		fmt.Println("  1. gcloud auth login")
+		fmt.Println("  2. gcloud auth application-default login")
+		fmt.Printf("  3. gctx save %s\n", result.Account.Name)
+		return nil
 	},
 }
 
 func init() {
 	createCmd.Flags().BoolVar(&autoSave, "auto-save", false,
 		"Automatically run auth and save credentials")
+	createCmd.Flags().StringArrayVar(&createProperties, "set", nil,
+		"gcloud property to store on the account, as section/property=value (repeatable)")
 }
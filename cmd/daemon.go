@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/k0wl0n/gctx/pkg/adc"
+	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/gcloud"
+	"github.com/k0wl0n/gctx/pkg/manager"
+	"github.com/k0wl0n/gctx/pkg/watcher"
+	"github.com/spf13/cobra"
+)
+
+var daemonForeground bool
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Auto-save ADC changes to the active account in the background",
+	Long: `The daemon watches the default ADC file and the active gcloud
+configuration. When credentials change outside gctx, e.g. after running
+'gcloud auth application-default login' by hand, they are saved to the
+active account as 'gctx save' would.`,
+	Example: `  # Start the daemon in the background
+  gctx daemon start
+
+  # Run it in the foreground, logging to the terminal
+  gctx daemon start --foreground
+
+  # Check whether it is running, then stop it
+  gctx daemon status
+  gctx daemon stop`,
+}
+
+var daemonStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the daemon",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if p, err := runningDaemon(); err != nil {
+			return err
+		} else if p != nil {
+			return fmt.Errorf("daemon already running (pid %d)", p.Pid)
+		}
+
+		if daemonForeground {
+			return runDaemon(cmd, log.New(os.Stdout, "", log.LstdFlags))
+		}
+
+		pid, logPath, err := startDaemon()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Daemon started (pid %d), logging to %s\n", pid, logPath)
+		return nil
+	},
+}
+
+var daemonStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the daemon",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := runningDaemon()
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			fmt.Println("Daemon is not running")
+			return nil
+		}
+
+		if err := terminate(p); err != nil {
+			return fmt.Errorf("failed to stop daemon: %w", err)
+		}
+		fmt.Printf("Stopped daemon (pid %d)\n", p.Pid)
+		return nil
+	},
+}
+
+var daemonStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the daemon is running",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := runningDaemon()
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			fmt.Println("Daemon is not running")
+			return nil
+		}
+
+		fmt.Printf("Daemon running (pid %d)\n", p.Pid)
+		if logPath, err := daemonPath("daemon.log"); err == nil {
+			fmt.Printf("Log: %s\n", logPath)
+		}
+		return nil
+	},
+}
+
+// logReporter writes manager progress to the daemon log
+type logReporter struct {
+	logger *log.Logger
+}
+
+func (r logReporter) Infof(format string, args ...any) {
+	r.logger.Printf(format, args...)
+}
+
+func (r logReporter) Warnf(format string, args ...any) {
+	r.logger.Printf("Warning: "+format, args...)
+}
+
+// runDaemon watches ADC and the gcloud active configuration until
+// interrupted, capturing new credentials into the active account
+func runDaemon(cmd *cobra.Command, logger *log.Logger) error {
+	pidPath, err := daemonPath("daemon.pid")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(pidPath, []byte(strconv.Itoa(os.Getpid())+"\n"), 0600); err != nil {
+		return err
+	}
+	defer os.Remove(pidPath)
+
+	ctx := cmd.Context()
+
+	adcPath := adc.GetDefaultADCPath()
+	adcEvents, err := watcher.Watch(ctx, adcPath)
+	if err != nil {
+		return err
+	}
+	configEvents, err := watcher.Watch(ctx, gcloud.GetActiveConfigPath())
+	if err != nil {
+		return err
+	}
+
+	reporter := logReporter{logger: logger}
+	capture := func() {
+		m, err := manager.New(manager.WithReporter(reporter), manager.WithCommand(cmd.Name()))
+		if err != nil {
+			reporter.Warnf("%v", err)
+			return
+		}
+		result, err := m.CaptureADC(cmd.Context())
+		if err != nil {
+			reporter.Warnf("failed to save ADC: %v", err)
+			return
+		}
+		if result != nil {
+			logger.Printf("Saved ADC for %s to %s (%s)", result.Account, result.Path, result.Email)
+		}
+	}
+
+	logger.Printf("Watching %s (pid %d)", adcPath, os.Getpid())
+	capture()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Printf("Stopping")
+			return nil
+		case event, ok := <-adcEvents:
+			if !ok {
+				return nil
+			}
+			switch {
+			case event.State == nil:
+				logger.Printf("ADC file removed")
+			case event.Err != nil:
+				logger.Printf("Warning: ignoring invalid ADC file: %v", event.Err)
+			default:
+				capture()
+			}
+		case _, ok := <-configEvents:
+			if !ok {
+				return nil
+			}
+			if name, err := gcloud.ReadActiveConfig(); err == nil {
+				logger.Printf("gcloud configuration: %s", name)
+			}
+			capture()
+		}
+	}
+}
+
+// startDaemon launches a detached foreground daemon logging to daemon.log
+func startDaemon() (int, string, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return 0, "", err
+	}
+	logPath, err := daemonPath("daemon.log")
+	if err != nil {
+		return 0, "", err
+	}
+	logFile, err := os.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return 0, "", err
+	}
+	defer logFile.Close()
+
+	child := exec.Command(self, "daemon", "start", "--foreground")
+	child.Stdout = logFile
+	child.Stderr = logFile
+	child.SysProcAttr = detachedProcAttr()
+	if err := child.Start(); err != nil {
+		return 0, "", err
+	}
+	pid := child.Process.Pid
+	return pid, logPath, child.Process.Release()
+}
+
+// runningDaemon returns the daemon process recorded in the pidfile, or nil
+// if it is not running. A stale pidfile is removed.
+func runningDaemon() (*os.Process, error) {
+	pidPath, err := daemonPath("daemon.pid")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(pidPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pidfile %s: %w", pidPath, err)
+	}
+	if p, err := os.FindProcess(pid); err == nil && processAlive(p) {
+		return p, nil
+	}
+
+	os.Remove(pidPath)
+	return nil, nil
+}
+
+// daemonPath returns the location of a daemon file in the gctx directory
+func daemonPath(name string) (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+func init() {
+	daemonStartCmd.Flags().BoolVar(&daemonForeground, "foreground", false,
+		"Run in the foreground instead of detaching")
+
+	daemonCmd.AddCommand(daemonStartCmd)
+	daemonCmd.AddCommand(daemonStopCmd)
+	daemonCmd.AddCommand(daemonStatusCmd)
+}
@@ -1,7 +1,8 @@
 package cmd
 
 import (
-	"github.com/k0wl0n/gctx/pkg/manager"
+	"fmt"
+
 	"github.com/spf13/cobra"
 )
 
@@ -17,12 +18,17 @@ var deleteCmd = &cobra.Command{
   gctx delete my-account --gcloud-config`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		m, err := manager.New()
+		m, err := newManager(cmd)
 		if err != nil {
 			return err
 		}
 
-		return m.DeleteAccount(args[0], deleteGcloudConfig)
+		if err := m.DeleteAccount(cmd.Context(), args[0], deleteGcloudConfig); err != nil {
+			return err
+		}
+
+		fmt.Printf("Deleted account: %s\n", args[0])
+		return nil
 	},
 }
 
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+)
+
+// detachedProcAttr starts a child in its own session so it outlives the
+// terminal gctx was started from
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
+
+// processAlive reports whether p is still running
+func processAlive(p *os.Process) bool {
+	return p.Signal(syscall.Signal(0)) == nil
+}
+
+// terminate asks p to shut down gracefully
+func terminate(p *os.Process) error {
+	return p.Signal(syscall.SIGTERM)
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/k0wl0n/gctx/pkg/docker"
+	"github.com/k0wl0n/gctx/pkg/manager"
+	"github.com/spf13/cobra"
+)
+
+// dockerHelperName is the binary name docker runs for the "gctx" helper
+const dockerHelperName = "docker-credential-gctx"
+
+// errCredentialsNotFound is the message docker expects from a helper
+// without credentials for a registry
+const errCredentialsNotFound = "credentials not found in native keychain"
+
+var dockerCmd = &cobra.Command{
+	Use:   "docker",
+	Short: "Use account credentials for container registries",
+	Long: `Link container registries such as Artifact Registry to accounts. Docker then
+gets an access token minted from the linked account's saved credentials,
+whichever account is active.
+
+This requires gctx to be reachable as docker-credential-gctx on the PATH:
+
+  ln -s "$(command -v gctx)" /usr/local/bin/docker-credential-gctx`,
+}
+
+var dockerConfigureCmd = &cobra.Command{
+	Use:   "configure <account-name> <registry>...",
+	Short: "Link registries to an account and register gctx with docker",
+	Example: `  # Push to Artifact Registry in europe-west1 as 'work'
+  gctx docker configure work europe-west1-docker.pkg.dev`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		hosts := registryHosts(args[1:])
+		if err := m.LinkRegistries(args[0], hosts); err != nil {
+			return err
+		}
+		if err := docker.SetCredHelpers(hosts, "gctx"); err != nil {
+			return fmt.Errorf("failed to update docker config: %w", err)
+		}
+
+		fmt.Printf("Registries %s use account %s\n", strings.Join(hosts, ", "), args[0])
+		fmt.Printf("Updated %s\n", docker.GetConfigPath())
+		return nil
+	},
+}
+
+var dockerUnconfigureCmd = &cobra.Command{
+	Use:   "unconfigure <registry>...",
+	Short: "Unlink registries and unregister gctx from docker for them",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		hosts := registryHosts(args)
+		if err := m.UnlinkRegistries(hosts); err != nil {
+			return err
+		}
+		if err := docker.SetCredHelpers(hosts, ""); err != nil {
+			return fmt.Errorf("failed to update docker config: %w", err)
+		}
+
+		fmt.Printf("Unlinked %s\n", strings.Join(hosts, ", "))
+		return nil
+	},
+}
+
+var dockerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registries linked to accounts",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		registries := m.Registries()
+		if len(registries) == 0 {
+			fmt.Println("No linked registries")
+			return nil
+		}
+
+		hosts := make([]string, 0, len(registries))
+		for host := range registries {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+		for _, host := range hosts {
+			fmt.Printf("  %s → %s\n", host, registries[host])
+		}
+		return nil
+	},
+}
+
+// dockerCredentialCmd implements docker's credential helper protocol. It is
+// run as "docker-credential-gctx <action>" with its input on stdin.
+var dockerCredentialCmd = &cobra.Command{
+	Use:         "docker-credential <get|store|erase|list>",
+	Short:       "Docker credential helper protocol",
+	Hidden:      true,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{cachedOnly: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		m, err := manager.New()
+		if err != nil {
+			return err
+		}
+
+		switch args[0] {
+		case "get":
+			serverURL, err := readLine(os.Stdin)
+			if err != nil {
+				return err
+			}
+
+			token, err := m.RegistryToken(cmd.Context(), serverURL)
+			if errors.Is(err, manager.ErrRegistryNotLinked) {
+				// Docker only recognises this exact message, on stdout
+				fmt.Println(errCredentialsNotFound)
+				return err
+			}
+			if err != nil {
+				fmt.Println(err)
+				return err
+			}
+
+			return json.NewEncoder(os.Stdout).Encode(map[string]string{
+				"ServerURL": serverURL,
+				"Username":  manager.RegistryUsername,
+				"Secret":    token.AccessToken,
+			})
+
+		case "list":
+			listed := make(map[string]string)
+			for host := range m.Registries() {
+				listed[host] = manager.RegistryUsername
+			}
+			return json.NewEncoder(os.Stdout).Encode(listed)
+
+		case "store", "erase":
+			// Credentials are minted from saved ADC on every get; there is
+			// nothing to store or erase (e.g. after docker login/logout)
+			io.Copy(io.Discard, os.Stdin)
+			return nil
+		}
+
+		return fmt.Errorf("unknown credential helper action: %s", args[0])
+	},
+}
+
+// registryHosts normalises registry arguments to host names
+func registryHosts(registries []string) []string {
+	hosts := make([]string, len(registries))
+	for i, registry := range registries {
+		hosts[i] = manager.RegistryHost(registry)
+	}
+	return hosts
+}
+
+// readLine reads a single trimmed line, as sent by credential helper clients
+func readLine(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func init() {
+	dockerCmd.AddCommand(dockerConfigureCmd)
+	dockerCmd.AddCommand(dockerUnconfigureCmd)
+	dockerCmd.AddCommand(dockerListCmd)
+}
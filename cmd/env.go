@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/k0wl0n/gctx/pkg/dotenv"
+	"github.com/k0wl0n/gctx/pkg/manager"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envProfile string
+	envShell   string
+	envProject string
+	envWrite   string
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env <account-name>",
+	Short: "Print the environment that points a tool at an account",
+	Long: `Print the environment variables through which a tool picks up an account,
+without switching the globally active account. Profiles:
+
+  terraform    GOOGLE_APPLICATION_CREDENTIALS, GOOGLE_PROJECT, GOOGLE_REGION,
+               GOOGLE_ZONE, GOOGLE_IMPERSONATE_SERVICE_ACCOUNT, GOOGLE_BILLING_PROJECT
+  gcloud       CLOUDSDK_ACTIVE_CONFIG_NAME, CLOUDSDK_CORE_PROJECT and the
+               matching CLOUDSDK_* properties
+  client-libs  GOOGLE_APPLICATION_CREDENTIALS, GOOGLE_CLOUD_PROJECT,
+               GOOGLE_CLOUD_QUOTA_PROJECT
+  dotenv       all of the above, as KEY=value lines
+
+Region, zone and impersonation come from the account's compute/region,
+compute/zone and auth/impersonate_service_account properties (see gctx set).`,
+	Example: `  # Run terraform as 'work'
+  eval "$(gctx env work --profile terraform)"
+
+  # Write a .env file for the repository
+  gctx env work --profile dotenv --write .env`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, ok := hookScripts[envShell]; !ok {
+			return fmt.Errorf("unsupported shell: %s", envShell)
+		}
+
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		env, err := m.ProfileEnv(args[0], envProfile, envProject)
+		if err != nil {
+			return err
+		}
+
+		if envWrite != "" {
+			if err := dotenv.Update(envWrite, env); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote %d variables for %s to %s\n", len(env), args[0], envWrite)
+			return nil
+		}
+
+		if envProfile == manager.ProfileDotenv {
+			fmt.Print(dotenv.Format(env))
+			return nil
+		}
+		printExports(envShell, env)
+		return nil
+	},
+}
+
+func init() {
+	envCmd.Flags().StringVar(&envProfile, "profile", manager.ProfileClientLibs,
+		"Consumer to emit variables for: "+strings.Join(manager.Profiles, ", "))
+	envCmd.Flags().StringVar(&envShell, "shell", "bash",
+		"Shell syntax for the exports: bash, zsh or fish")
+	envCmd.Flags().StringVar(&envProject, "project", "",
+		"Project to use instead of the account's")
+	envCmd.Flags().StringVar(&envWrite, "write", "",
+		"Update this .env file instead of printing")
+}
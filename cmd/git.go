@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/k0wl0n/gctx/pkg/git"
+	"github.com/k0wl0n/gctx/pkg/manager"
+	"github.com/spf13/cobra"
+)
+
+// gitHelperName is the binary name git runs for the "gctx" helper
+const gitHelperName = "git-credential-gctx"
+
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Use account credentials for Google-hosted git repositories",
+	Long: `Answer git's credential requests for Cloud Source Repositories and Secure
+Source Manager with access tokens minted from an account's saved credentials,
+whichever account is active.
+
+The account is the one linked to the longest matching URL prefix or, failing
+that, the account of the repository's .gctx directory file.
+
+This requires gctx to be reachable as git-credential-gctx on the PATH:
+
+  ln -s "$(command -v gctx)" /usr/local/bin/git-credential-gctx`,
+}
+
+var gitConfigureCmd = &cobra.Command{
+	Use:   "configure <account-name> <url-prefix>...",
+	Short: "Link repository URLs to an account and register gctx with git",
+	Example: `  # Use 'work' for every repository of the 'my-project' project
+  gctx git configure work https://source.developers.google.com/p/my-project`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		prefixes := args[1:]
+		if err := m.LinkGitURLs(args[0], prefixes); err != nil {
+			return err
+		}
+		for _, prefix := range prefixes {
+			if err := git.ConfigureHelper(cmd.Context(), prefix, "gctx"); err != nil {
+				return fmt.Errorf("failed to update git config: %w", err)
+			}
+		}
+
+		fmt.Printf("Repositories under %s use account %s\n", strings.Join(prefixes, ", "), args[0])
+		return nil
+	},
+}
+
+var gitUnconfigureCmd = &cobra.Command{
+	Use:   "unconfigure <url-prefix>...",
+	Short: "Unlink repository URLs and unregister gctx from git for them",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		if err := m.UnlinkGitURLs(args); err != nil {
+			return err
+		}
+		for _, prefix := range args {
+			if err := git.UnconfigureHelper(cmd.Context(), prefix); err != nil {
+				return fmt.Errorf("failed to update git config: %w", err)
+			}
+		}
+
+		fmt.Printf("Unlinked %s\n", strings.Join(args, ", "))
+		return nil
+	},
+}
+
+var gitListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List repository URLs linked to accounts",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		links := m.GitURLs()
+		if len(links) == 0 {
+			fmt.Println("No linked repository URLs")
+			return nil
+		}
+
+		prefixes := make([]string, 0, len(links))
+		for prefix := range links {
+			prefixes = append(prefixes, prefix)
+		}
+		sort.Strings(prefixes)
+		for _, prefix := range prefixes {
+			fmt.Printf("  %s → %s\n", prefix, links[prefix])
+		}
+		return nil
+	},
+}
+
+// gitCredentialCmd implements git's credential helper protocol. It is run
+// as "git-credential-gctx <action>" in the repository, with the request on
+// stdin.
+var gitCredentialCmd = &cobra.Command{
+	Use:         "git-credential <get|store|erase>",
+	Short:       "Git credential helper protocol",
+	Hidden:      true,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{cachedOnly: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		request, err := readCredentialRequest(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		// Credentials are minted on every get; git's store and erase
+		// (after a successful or rejected attempt) have nothing to act on
+		if args[0] != "get" {
+			return nil
+		}
+
+		m, err := manager.New()
+		if err != nil {
+			return err
+		}
+
+		dir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		cred, err := m.GitCredentials(cmd.Context(), credentialURL(request), dir)
+		if err != nil || cred == nil {
+			// No output lets git fall back to its other helpers
+			return err
+		}
+
+		fmt.Printf("username=%s\n", cred.Username)
+		fmt.Printf("password=%s\n", cred.Password)
+		if !cred.Expiry.IsZero() {
+			fmt.Printf("password_expiry_utc=%d\n", cred.Expiry.Unix())
+		}
+		return nil
+	},
+}
+
+// readCredentialRequest parses key=value lines up to a blank line or EOF
+func readCredentialRequest(r io.Reader) (map[string]string, error) {
+	request := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			request[key] = value
+		}
+	}
+	return request, scanner.Err()
+}
+
+// credentialURL rebuilds the repository URL of a credential request
+func credentialURL(request map[string]string) string {
+	if u := request["url"]; u != "" {
+		return u
+	}
+
+	u := request["protocol"] + "://" + request["host"]
+	if path := request["path"]; path != "" {
+		u += "/" + strings.TrimPrefix(path, "/")
+	}
+	return u
+}
+
+func init() {
+	gitCmd.AddCommand(gitConfigureCmd)
+	gitCmd.AddCommand(gitUnconfigureCmd)
+	gitCmd.AddCommand(gitListCmd)
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var historyLimit int
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show account switch history",
+	Example: `  # Show the last 20 switches
+  gctx history
+
+  # Show the last 5 switches
+  gctx history -n 5`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		entries, err := m.History()
+		if err != nil {
+			return err
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No switch history")
+			return nil
+		}
+
+		if historyLimit > 0 && len(entries) > historyLimit {
+			entries = entries[:historyLimit]
+		}
+
+		for _, e := range entries {
+			from := e.From
+			if from == "" {
+				from = "-"
+			}
+			fmt.Printf("  %s  %s → %s  (%s)\n",
+				e.Time.Local().Format("2006-01-02 15:04:05"), from, e.To, e.Source)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 20,
+		"Number of entries to show (0 for all)")
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// dirEnvVar holds the directory file whose environment is currently
+// exported, with a hash of its content so that edits are picked up
+const dirEnvVar = "GCTX_DIR"
+
+// dirEnvKeys lists every variable the hook may export, so that leaving a
+// directory removes them again
+var dirEnvKeys = []string{
+	"GCTX_ACCOUNT",
+	"CLOUDSDK_ACTIVE_CONFIG_NAME",
+	"CLOUDSDK_CORE_PROJECT",
+	"GOOGLE_CLOUD_PROJECT",
+	"GOOGLE_APPLICATION_CREDENTIALS",
+	dirEnvVar,
+}
+
+var hookScripts = map[string]string{
+	"bash": `_gctx_hook() {
+  local previous_exit_status=$?
+  eval "$(%[1]s hook-env bash)"
+  return $previous_exit_status
+}
+if [[ ";${PROMPT_COMMAND[*]:-};" != *";_gctx_hook;"* ]]; then
+  PROMPT_COMMAND="_gctx_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+`,
+	"zsh": `_gctx_hook() {
+  eval "$(%[1]s hook-env zsh)"
+}
+typeset -ag precmd_functions chpwd_functions
+if (( ! ${precmd_functions[(I)_gctx_hook]} )); then
+  precmd_functions=(_gctx_hook $precmd_functions)
+fi
+if (( ! ${chpwd_functions[(I)_gctx_hook]} )); then
+  chpwd_functions=(_gctx_hook $chpwd_functions)
+fi
+`,
+	"fish": `function __gctx_hook --on-variable PWD --on-event fish_prompt
+    %[1]s hook-env fish | source
+end
+`,
+}
+
+var hookCmd = &cobra.Command{
+	Use:   "hook <shell>",
+	Short: "Print a shell hook that applies .gctx directory files",
+	Long: `Print a shell hook that selects an account per directory.
+
+On every prompt the hook looks for a .gctx or .gctx.yaml file in the current
+directory or any parent, and exports CLOUDSDK_ACTIVE_CONFIG_NAME,
+GOOGLE_APPLICATION_CREDENTIALS and the project variables for the account it
+names. The globally active account is left untouched. The variables are
+removed again when leaving the directory.
+
+A .gctx file contains either just the account name, or YAML:
+
+  account: work
+  project: work-staging`,
+	Example: `  # bash (~/.bashrc)
+  eval "$(gctx hook bash)"
+
+  # zsh (~/.zshrc)
+  eval "$(gctx hook zsh)"
+
+  # fish (~/.config/fish/config.fish)
+  gctx hook fish | source`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		script, ok := hookScripts[args[0]]
+		if !ok {
+			return fmt.Errorf("unsupported shell: %s", args[0])
+		}
+
+		self, err := os.Executable()
+		if err != nil {
+			self = "gctx"
+		}
+
+		fmt.Printf(script, shellQuote(args[0], self))
+		return nil
+	},
+}
+
+var hookEnvCmd = &cobra.Command{
+	Use:         "hook-env <shell>",
+	Short:       "Print environment changes for the current directory",
+	Hidden:      true,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{cachedOnly: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		shell := args[0]
+		if _, ok := hookScripts[shell]; !ok {
+			return fmt.Errorf("unsupported shell: %s", shell)
+		}
+
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		current := os.Getenv(dirEnvVar)
+		dirCtx, err := m.ResolveDirectory(cwd)
+		if err != nil {
+			// Drop the previous directory's account rather than keep
+			// running commands as it
+			fmt.Fprintf(os.Stderr, "gctx: %v\n", err)
+			if current != "" {
+				printUnsets(shell)
+			}
+			return nil
+		}
+
+		if dirCtx == nil {
+			if current != "" {
+				printUnsets(shell)
+			}
+			return nil
+		}
+
+		marker := dirMarker(dirCtx.File.Path)
+		if marker == current {
+			return nil
+		}
+
+		env, err := m.AccountEnv(dirCtx.Account.Name, dirCtx.Project)
+		if err != nil {
+			if current != "" {
+				printUnsets(shell)
+			}
+			return err
+		}
+		env[dirEnvVar] = marker
+
+		if current != "" {
+			printUnsets(shell)
+		}
+		printExports(shell, env)
+		return nil
+	},
+}
+
+// dirMarker identifies a directory file and its content as "<path>#<hash>"
+func dirMarker(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return path
+	}
+	sum := sha256.Sum256(data)
+	return path + "#" + hex.EncodeToString(sum[:8])
+}
+
+func printUnsets(shell string) {
+	for _, key := range dirEnvKeys {
+		if shell == "fish" {
+			fmt.Printf("set -e %s;\n", key)
+		} else {
+			fmt.Printf("unset %s;\n", key)
+		}
+	}
+}
+
+func printExports(shell string, env map[string]string) {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := shellQuote(shell, env[key])
+		if shell == "fish" {
+			fmt.Printf("set -gx %s %s;\n", key, value)
+		} else {
+			fmt.Printf("export %s=%s;\n", key, value)
+		}
+	}
+}
+
+// shellQuote quotes s as a single word for the given shell
+func shellQuote(shell, s string) string {
+	if shell == "fish" {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+		return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
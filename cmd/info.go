@@ -1,8 +1,15 @@
 package cmd
 
 import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/selector"
 	"github.com/spf13/cobra"
-	"github.com/k0wl0n/gctx/pkg/manager"
 )
 
 var infoCmd = &cobra.Command{
@@ -10,13 +17,94 @@ var infoCmd = &cobra.Command{
 	Short: "Show detailed account information",
 	Example: `  # Show details for 'my-account'
   gctx info my-account`,
-	Args:  cobra.ExactArgs(1),
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		m, err := manager.New()
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		info, err := m.GetAccountInfo(args[0])
 		if err != nil {
 			return err
 		}
+		account := info.Account
+
+		fmt.Printf("\nAccount: %s\n", account.Name)
+		fmt.Println(strings.Repeat("=", 50))
+		fmt.Printf("Project ID:       %s\n", account.ProjectID)
+		if projects := account.AllProjects(); len(projects) > 1 {
+			fmt.Printf("Projects:         %s\n", strings.Join(projects, ", "))
+		}
+		fmt.Printf("Config Name:      %s\n", account.ConfigName)
+		fmt.Printf("Quota Project:    %s\n", account.EffectiveQuotaProject())
+
+		if account.Email != "" {
+			fmt.Printf("Email:            %s\n", account.Email)
+		}
+
+		if account.ADCPath != "" {
+			fmt.Printf("ADC Path:         %s\n", account.ADCPath)
+
+			if !info.ADCModTime.IsZero() {
+				fmt.Printf("ADC Last Modified: %s\n",
+					info.ADCModTime.Format("2006-01-02 15:04:05"))
+			}
+		}
+
+		fmt.Printf("Created:          %s\n",
+			account.CreatedAt.Format("2006-01-02 15:04:05"))
+
+		if account.CredentialStatus != config.CredentialUnknown {
+			status := account.CredentialStatus
+			if status == config.CredentialNeedsLogin {
+				status = fmt.Sprintf("needs login%s (run: gctx login %s)", needsLoginMarker, account.Name)
+			}
+			fmt.Printf("Credentials:      %s (checked %s)\n", status,
+				account.CredentialCheckedAt.Local().Format("2006-01-02 15:04:05"))
+		}
+
+		if len(account.Properties) > 0 {
+			fmt.Println("Properties:")
+			for _, property := range slices.Sorted(maps.Keys(account.Properties)) {
+				fmt.Printf("  %-24s %s\n", property, account.Properties[property])
+			}
+		}
+
+		if len(account.Tags) > 0 {
+			fmt.Printf("Tags:             %s\n", selector.FormatTags(account.Tags))
+		}
+
+		if account.KubeContext != "" {
+			fmt.Printf("Kube Context:     %s\n", account.KubeContext)
+		}
+
+		if account.Protected {
+			fmt.Printf("Protected:        yes%s\n", protectedMarker)
+			if account.ExpireAfter > 0 {
+				fmt.Printf("Expire After:     %s\n", time.Duration(account.ExpireAfter))
+			}
+		}
+
+		if m.DefaultAccount() == account.Name {
+			fmt.Println("\nThis is the default account.")
+		}
+
+		if len(info.Warnings) > 0 {
+			fmt.Println()
+			for _, w := range info.Warnings {
+				fmt.Printf("Warning: %s\n", w)
+			}
+		}
+
+		if info.Active {
+			fmt.Println("\nThis is the active account.")
+			if expiry := m.ActiveExpiry(); expiry != nil {
+				fmt.Printf("Reverts to '%s' at %s\n", expiry.RevertTo,
+					expiry.At.Local().Format("2006-01-02 15:04:05"))
+			}
+		}
 
-		return m.ShowAccountInfo(args[0])
+		return nil
 	},
 }
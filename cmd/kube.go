@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var kubePinAuth bool
+
+var kubeCmd = &cobra.Command{
+	Use:   "kube",
+	Short: "Link kubectl contexts to accounts",
+	Long: `Link kubectl contexts to accounts. Switching to an account then makes its
+linked context the current-context of ~/.kube/config (or the files listed in
+KUBECONFIG).`,
+}
+
+var kubeLinkCmd = &cobra.Command{
+	Use:   "link <account-name> <context>",
+	Short: "Link a kubectl context to an account",
+	Example: `  # Make 'gke_my-project_europe-west1_prod' current when switching to 'prod'
+  gctx kube link prod gke_my-project_europe-west1_prod
+
+  # Also authenticate the context as 'prod', whichever account is active
+  gctx kube link prod gke_my-project_europe-west1_prod --pin-auth`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		account, err := m.LinkKubeContext(args[0], args[1], kubePinAuth)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Linked kubectl context '%s' to %s\n", account.KubeContext, account.Name)
+		return nil
+	},
+}
+
+var kubeUnlinkCmd = &cobra.Command{
+	Use:   "unlink <account-name>",
+	Short: "Remove the kubectl context linked to an account",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		if err := m.UnlinkKubeContext(args[0]); err != nil {
+			return err
+		}
+
+		fmt.Printf("Unlinked kubectl context from %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	kubeLinkCmd.Flags().BoolVar(&kubePinAuth, "pin-auth", false,
+		"Point the context at a gke-gcloud-auth-plugin user bound to the account's gcloud configuration")
+
+	kubeCmd.AddCommand(kubeLinkCmd)
+	kubeCmd.AddCommand(kubeUnlinkCmd)
+}
@@ -1,21 +1,84 @@
 package cmd
 
 import (
+	"fmt"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/selector"
 	"github.com/spf13/cobra"
-	"github.com/k0wl0n/gctx/pkg/manager"
 )
 
+var listSelector string
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all configured accounts",
 	Example: `  # List all accounts
-  gctx list`,
+  gctx list
+
+  # List production accounts not owned by the data team
+  gctx list --selector env=prod,team!=data`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		m, err := manager.New()
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		sel, err := selector.Parse(listSelector)
 		if err != nil {
 			return err
 		}
 
-		return m.ListAccounts()
+		accounts := m.SelectAccounts(sel)
+		if len(accounts) == 0 {
+			if len(sel) > 0 {
+				fmt.Printf("No accounts match selector '%s'\n", sel)
+			} else {
+				fmt.Println("No accounts configured")
+			}
+			return nil
+		}
+
+		fmt.Println("\nConfigured Accounts:")
+		fmt.Println("===================")
+
+		for _, acc := range accounts {
+			active := ""
+			if m.IsActive(acc.Name) {
+				active = " ← active"
+			}
+
+			email := ""
+			if acc.Email != "" {
+				email = fmt.Sprintf(" [%s]", acc.Email)
+			}
+
+			protected := ""
+			if acc.Protected {
+				protected = protectedMarker
+			}
+			if acc.CredentialStatus == config.CredentialNeedsLogin {
+				protected += needsLoginMarker + " needs login"
+			}
+
+			tags := ""
+			if len(acc.Tags) > 0 {
+				tags = fmt.Sprintf(" {%s}", selector.FormatTags(acc.Tags))
+			}
+
+			project := acc.ProjectID
+			if n := len(acc.AllProjects()) - 1; n > 0 {
+				project = fmt.Sprintf("%s +%d", project, n)
+			}
+
+			fmt.Printf("  %s%s (%s)%s%s%s\n",
+				acc.Name, protected, project, email, tags, active)
+		}
+
+		return nil
 	},
 }
+
+func init() {
+	listCmd.Flags().StringVarP(&listSelector, "selector", "l", "", selectorUsage)
+}
@@ -1,24 +1,118 @@
 package cmd
 
 import (
+	"fmt"
+
+	"github.com/k0wl0n/gctx/pkg/selector"
 	"github.com/spf13/cobra"
-	"github.com/k0wl0n/gctx/pkg/manager"
+)
+
+var (
+	loginExpired  bool
+	loginAll      bool
+	loginSelector string
 )
 
 var loginCmd = &cobra.Command{
-	Use:   "login <account-name>",
+	Use:   "login [account-name]",
 	Short: "Re-authenticate an existing account",
 	Long: `Run the authentication flow (gcloud auth login + application-default login)
-for an existing account and update the saved credentials.`,
+for an existing account and update the saved credentials.
+
+Several accounts can be re-authenticated in one go: --all selects every
+account, --selector the matching ones, and --expired only those whose saved
+credentials no longer work (combine with --selector to narrow it down). The
+accounts are logged in one after another and the originally active account
+is restored at the end.`,
 	Example: `  # Re-authenticate 'my-account'
-  gctx login my-account`,
-	Args: cobra.ExactArgs(1),
+  gctx login my-account
+
+  # Re-authenticate every account whose credentials expired
+  gctx login --expired
+
+  # Re-authenticate expired production accounts
+  gctx login --expired --selector env=prod`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if loginExpired || loginAll || loginSelector != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		m, err := manager.New()
+		m, err := newManager(cmd)
 		if err != nil {
 			return err
 		}
 
-		return m.Login(args[0])
+		if len(args) == 1 {
+			result, err := m.Login(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			printLoginResult(result)
+			return nil
+		}
+
+		if loginAll && loginSelector != "" {
+			return fmt.Errorf("--all and --selector are mutually exclusive")
+		}
+
+		sel, err := selector.Parse(loginSelector)
+		if err != nil {
+			return err
+		}
+
+		var names []string
+		for _, acc := range m.SelectAccounts(sel) {
+			names = append(names, acc.Name)
+		}
+
+		if loginExpired {
+			fmt.Printf("Checking credentials of %d account(s)...\n", len(names))
+			if names, err = m.AccountsNeedingLogin(cmd.Context(), names); err != nil {
+				return err
+			}
+		}
+
+		if len(names) == 0 {
+			fmt.Println("No accounts need a login")
+			return nil
+		}
+
+		results := m.LoginAccounts(cmd.Context(), names)
+
+		fmt.Println("\nSummary:")
+		failed := 0
+		for _, r := range results {
+			if r.Err != nil {
+				failed++
+				fmt.Printf("  ✗ %s: %v\n", r.Account, r.Err)
+			} else {
+				fmt.Printf("  ✓ %s\n", r.Account)
+			}
+		}
+
+		skipped := len(names) - len(results)
+		for _, name := range names[len(results):] {
+			fmt.Printf("  - %s: skipped\n", name)
+		}
+
+		switch {
+		case skipped > 0:
+			cmd.SilenceUsage = true
+			return fmt.Errorf("interrupted, %d login(s) skipped", skipped)
+		case failed > 0:
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d of %d login(s) failed", failed, len(results))
+		}
+		return nil
 	},
 }
+
+func init() {
+	loginCmd.Flags().BoolVar(&loginExpired, "expired", false,
+		"Only re-authenticate accounts whose credentials need a login")
+	loginCmd.Flags().BoolVar(&loginAll, "all", false, "Re-authenticate every account")
+	loginCmd.Flags().StringVarP(&loginSelector, "selector", "l", "", selectorUsage)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/k0wl0n/gctx/pkg/manager"
+	"github.com/spf13/cobra"
+)
+
+// consoleReporter renders manager progress messages on the terminal
+type consoleReporter struct {
+	out io.Writer
+	err io.Writer
+}
+
+func (r consoleReporter) Infof(format string, args ...any) {
+	fmt.Fprintf(r.out, format+"\n", args...)
+}
+
+func (r consoleReporter) Warnf(format string, args ...any) {
+	fmt.Fprintf(r.err, "Warning: "+format+"\n", args...)
+}
+
+// newManager loads the configuration and wires progress output to the terminal
+func newManager(cmd *cobra.Command) (*manager.Manager, error) {
+	return manager.New(
+		manager.WithReporter(consoleReporter{
+			out: os.Stdout,
+			err: os.Stderr,
+		}),
+		manager.WithCommand(cmd.Name()),
+		manager.WithConfirmer(terminalConfirmer{
+			in:  os.Stdin,
+			out: os.Stderr,
+			yes: assumeYes,
+		}),
+	)
+}
+
+// printLoginResult renders the outcome of an authentication flow
+func printLoginResult(result *manager.LoginResult) {
+	name := result.Account.Name
+
+	fmt.Printf("ADC credentials auto-saved for: %s\n", name)
+	fmt.Printf("Saved to: %s\n\n", result.ADCPath)
+
+	if len(result.Warnings) > 0 {
+		fmt.Println("Warnings:")
+		for _, w := range result.Warnings {
+			fmt.Printf("   %s\n", w)
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("Account '%s' is ready to use!\n", name)
+	fmt.Printf("Run: gctx switch %s\n", name)
+}
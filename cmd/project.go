@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	projectAccount string
+	projectForget  bool
+)
+
+var projectCmd = &cobra.Command{
+	Use:   "project [project-id]",
+	Short: "Change the project of the active account",
+	Long: `Change the project of the active account (or --account). This updates the
+gcloud core/project property and the quota_project_id of the ADC credentials,
+and remembers the project as a favourite. Without an argument, the favourite
+projects are offered in a fuzzy finder.`,
+	Example: `  # Use another project with the active account
+  gctx project my-other-project
+
+  # Pick among favourite projects
+  gctx project
+
+  # Remove a favourite project of 'my-account'
+  gctx project old-project --account my-account --forget`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		if projectForget {
+			if len(args) == 0 {
+				return fmt.Errorf("--forget requires a project id")
+			}
+			if err := m.ForgetProject(projectAccount, args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("Removed favourite project: %s\n", args[0])
+			return nil
+		}
+
+		projectID := ""
+		if len(args) > 0 {
+			projectID = args[0]
+		} else {
+			selected, err := m.SelectProjectInteractive(cmd.Context(), projectAccount)
+			if err != nil {
+				return err
+			}
+			projectID = selected
+		}
+
+		account, err := m.SetProject(cmd.Context(), projectAccount, projectID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Project of '%s' set to: %s\n", account.Name, account.ProjectID)
+		return nil
+	},
+}
+
+func init() {
+	projectCmd.Flags().StringVarP(&projectAccount, "account", "a", "",
+		"Account to change (defaults to the active account)")
+	projectCmd.Flags().BoolVar(&projectForget, "forget", false,
+		"Remove the project from the account's favourites")
+}
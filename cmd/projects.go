@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var projectsRefresh bool
+
+var projectsCmd = &cobra.Command{
+	Use:   "projects [account-name]",
+	Short: "List projects an account can access",
+	Long: `List the projects an account's saved credentials can access, using the
+Cloud Resource Manager API. Results are cached (projects_cache_ttl in the
+config, 1h by default); use --refresh to bypass the cache.
+
+The API endpoints can be overridden with the GCTX_TOKEN_ENDPOINT and
+GCTX_RESOURCE_MANAGER_ENDPOINT environment variables, or the "endpoints"
+section of the config file.`,
+	Example: `  # List projects of the active account
+  gctx projects
+
+  # List projects of 'my-account', ignoring the cache
+  gctx projects my-account --refresh`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		name := m.ActiveAccount()
+		if len(args) > 0 {
+			name = args[0]
+		}
+		if name == "" {
+			return fmt.Errorf("no active account; pass an account name")
+		}
+
+		projects, err := m.DiscoverProjects(cmd.Context(), name, projectsRefresh)
+		if err != nil {
+			return err
+		}
+
+		if len(projects) == 0 {
+			fmt.Printf("Account '%s' cannot access any project\n", name)
+			return nil
+		}
+
+		for _, p := range projects {
+			fmt.Printf("  %-32s %s\n", p.ProjectID, p.Name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	projectsCmd.Flags().BoolVar(&projectsRefresh, "refresh", false,
+		"Ignore cached results")
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/k0wl0n/gctx/pkg/manager"
+	"github.com/spf13/cobra"
+)
+
+const (
+	protectedMarker  = " ⚠"
+	needsLoginMarker = " ✗"
+)
+
+var promptFormat string
+
+var promptSnippets = map[string]string{
+	"bash": `# ~/.bashrc
+__gctx_ps1() {
+  gctx prompt --format '({name}:{project}{marker}) ' 2>/dev/null
+}
+PS1='$(__gctx_ps1)'"$PS1"
+`,
+	"zsh": `# ~/.zshrc
+setopt PROMPT_SUBST
+__gctx_ps1() {
+  gctx prompt --format '({name}:{project}{marker}) ' 2>/dev/null
+}
+PROMPT='$(__gctx_ps1)'"$PROMPT"
+`,
+	"fish": `# ~/.config/fish/config.fish
+if not functions -q __gctx_original_fish_prompt
+    functions -c fish_prompt __gctx_original_fish_prompt
+end
+function fish_prompt
+    gctx prompt --format '({name}:{project}{marker}) ' 2>/dev/null
+    __gctx_original_fish_prompt
+end
+`,
+	"starship": `# ~/.config/starship.toml
+[custom.gctx]
+command = "gctx prompt"
+when = true
+symbol = "☁️ "
+style = "bold blue"
+format = "[$symbol$output]($style) "
+`,
+}
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print a short account segment for shell prompts",
+	Long: `Print a short segment describing the current account for use in shell
+prompts. Only gctx's cached state is read and gcloud is never spawned, so it
+is safe to run on every prompt. Nothing is printed when no account is active.
+
+The account selected by a .gctx directory file (see 'gctx hook') takes
+precedence over the globally active account.
+
+Format placeholders:
+  {name}     account name
+  {project}  current project
+  {email}    account email
+  {config}   gcloud configuration name
+  {marker}   "` + protectedMarker + `" for protected accounts, "` + needsLoginMarker + `" when credentials need a login`,
+	Example: `  # Default segment, e.g. "work:my-project"
+  gctx prompt
+
+  # Custom format
+  gctx prompt --format '[{name}]{marker}'
+
+  # Print a ready-made snippet for your shell
+  gctx prompt init zsh`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{cachedOnly: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		// Respect the per-shell account exported by the directory hook
+		name, project := os.Getenv("GCTX_ACCOUNT"), ""
+		if name != "" {
+			project = os.Getenv("CLOUDSDK_CORE_PROJECT")
+		}
+
+		info, err := m.PromptInfo(name, project)
+		if err != nil || info == nil {
+			return err
+		}
+
+		fmt.Print(renderPrompt(promptFormat, info))
+		return nil
+	},
+}
+
+var promptInitCmd = &cobra.Command{
+	Use:       "init <bash|zsh|fish|starship>",
+	Short:     "Print a prompt snippet for a shell",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish", "starship"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snippet, ok := promptSnippets[args[0]]
+		if !ok {
+			return fmt.Errorf("unsupported shell: %s", args[0])
+		}
+
+		fmt.Print(snippet)
+		return nil
+	},
+}
+
+func renderPrompt(format string, info *manager.PromptInfo) string {
+	marker := ""
+	if info.Account.Protected {
+		marker += protectedMarker
+	}
+	if info.NeedsLogin {
+		marker += needsLoginMarker
+	}
+
+	return strings.NewReplacer(
+		"{name}", info.Account.Name,
+		"{project}", info.Project,
+		"{email}", info.Account.Email,
+		"{config}", info.Account.ConfigName,
+		"{marker}", marker,
+	).Replace(format)
+}
+
+func init() {
+	promptCmd.Flags().StringVar(&promptFormat, "format", "{name}:{project}{marker}",
+		"Segment format")
+	promptCmd.AddCommand(promptInitCmd)
+}
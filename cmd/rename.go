@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	renameGcloudConfig bool
+	cloneProject       string
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <old-name> <new-name>",
+	Short: "Rename an account",
+	Long: `Rename an account, keeping its saved ADC credentials and creation time.
+
+By default the gcloud configuration keeps its name. With --gcloud-config it
+is recreated as <new-name>-config with the same properties.`,
+	Example: `  # Fix a typo in an account name
+  gctx rename wrok work
+
+  # Also rename the gcloud configuration to work-config
+  gctx rename wrok work --gcloud-config`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		account, err := m.RenameAccount(cmd.Context(), args[0], args[1], renameGcloudConfig)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Renamed account: %s -> %s (gcloud config: %s)\n",
+			args[0], account.Name, account.ConfigName)
+		return nil
+	},
+}
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone <source-account> <new-account>",
+	Short: "Create an account reusing another account's credentials",
+	Example: `  # Use the 'work' credentials against another project
+  gctx clone work work-staging --project my-staging-project`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		account, err := m.CloneAccount(cmd.Context(), args[0], args[1], cloneProject)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Cloned account: %s -> %s (%s)\n",
+			args[0], account.Name, account.ProjectID)
+		fmt.Printf("Run: gctx switch %s\n", account.Name)
+		return nil
+	},
+}
+
+func init() {
+	renameCmd.Flags().BoolVar(&renameGcloudConfig, "gcloud-config", false,
+		"Also rename the gcloud configuration")
+	cloneCmd.Flags().StringVar(&cloneProject, "project", "",
+		"Project for the new account (defaults to the source account's project)")
+}
@@ -1,9 +1,23 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"github.com/spf13/cobra"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/adc"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// timeout bounds the whole command when non-zero
+	timeout       time.Duration
+	cancelTimeout context.CancelFunc = func() {}
 )
 
 var rootCmd = &cobra.Command{
@@ -11,10 +25,61 @@ var rootCmd = &cobra.Command{
 	Short: "Manage multiple GCP accounts seamlessly",
 	Long: `gctx is a CLI tool to manage multiple GCP accounts with
 automatic switching of both gcloud configurations and ADC credentials.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if timeout > 0 {
+			var ctx context.Context
+			ctx, cancelTimeout = context.WithTimeout(cmd.Context(), timeout)
+			cmd.SetContext(ctx)
+		}
+
+		if cmd.Annotations[cachedOnly] != "" || strings.HasPrefix(cmd.Name(), "__") {
+			return
+		}
+		revertExpired(cmd)
+	},
+}
+
+// cachedOnly annotates commands that must not touch gcloud (e.g. prompt
+// rendering), so pending expiries are not applied before them
+const cachedOnly = "gctx/cached-only"
+
+// revertExpired applies a pending expiry of the active account
+func revertExpired(cmd *cobra.Command) {
+	m, err := newManager(cmd)
+	if err != nil {
+		return
+	}
+
+	if _, err := m.RevertExpired(cmd.Context()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
 }
 
+// Execute runs the root command. Ctrl-C or SIGTERM cancel the command's
+// context so that gcloud children are stopped and partial changes rolled
+// back; a second signal terminates gctx immediately.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	// Invoked through a symlink as a credential helper
+	switch strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe") {
+	case dockerHelperName:
+		rootCmd.SetArgs(append([]string{"docker-credential"}, os.Args[1:]...))
+	case gitHelperName:
+		rootCmd.SetArgs(append([]string{"git-credential"}, os.Args[1:]...))
+	}
+
+	err := rootCmd.ExecuteContext(ctx)
+	cancelTimeout()
+	if ctx.Err() != nil {
+		adc.RemoveTempFiles()
+	}
+	return err
 }
 
 func init() {
@@ -27,6 +92,34 @@ func init() {
 	rootCmd.AddCommand(deleteCmd)
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(infoCmd)
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(hookCmd)
+	rootCmd.AddCommand(hookEnvCmd)
+	rootCmd.AddCommand(promptCmd)
+	rootCmd.AddCommand(setCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(revertExpiredCmd)
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(renameCmd)
+	rootCmd.AddCommand(cloneCmd)
+	rootCmd.AddCommand(projectCmd)
+	rootCmd.AddCommand(projectsCmd)
+	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(daemonCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(kubeCmd)
+	rootCmd.AddCommand(dockerCmd)
+	rootCmd.AddCommand(dockerCredentialCmd)
+	rootCmd.AddCommand(gitCmd)
+	rootCmd.AddCommand(gitCredentialCmd)
+	rootCmd.AddCommand(envCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importBundleCmd)
+
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false,
+		"Confirm protected account operations without prompting")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0,
+		"Abort the command after this duration (e.g. 30s); 0 means no limit")
 	rootCmd.AddCommand(completionCmd)
 }
 
@@ -1,25 +1,54 @@
 package cmd
 
 import (
+	"fmt"
+
+	"github.com/k0wl0n/gctx/pkg/selector"
 	"github.com/spf13/cobra"
-	"github.com/k0wl0n/gctx/pkg/manager"
 )
 
+var runSelector string
+
 var runCmd = &cobra.Command{
-	Use:   "run <account-name> <gcloud-args>...",
+	Use:   "run [account-name] <gcloud-args>...",
 	Short: "Run a gcloud command with specific account",
 	Example: `  # Run 'gcloud storage ls' as 'my-account'
   gctx run my-account storage ls
 
   # Run 'gcloud compute instances list' as 'dev-account'
-  gctx run dev-account compute instances list`,
-	Args:  cobra.MinimumNArgs(2),
+  gctx run dev-account compute instances list
+
+  # Run 'gcloud storage ls' for every production account
+  gctx run --selector env=prod storage ls`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if runSelector != "" {
+			return cobra.MinimumNArgs(1)(cmd, args)
+		}
+		return cobra.MinimumNArgs(2)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		m, err := manager.New()
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		if runSelector == "" {
+			return m.RunWithAccount(cmd.Context(), args[0], args[1:])
+		}
+
+		sel, err := selector.Parse(runSelector)
 		if err != nil {
 			return err
 		}
+		if len(sel) == 0 {
+			return fmt.Errorf("empty selector")
+		}
 
-		return m.RunWithAccount(args[0], args[1:])
+		return m.RunWithSelector(cmd.Context(), sel, args)
 	},
 }
+
+func init() {
+	runCmd.Flags().StringVarP(&runSelector, "selector", "l", "",
+		selectorUsage+"; runs the command for every matching account")
+}
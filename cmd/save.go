@@ -1,8 +1,9 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
-	"github.com/k0wl0n/gctx/pkg/manager"
 )
 
 var saveCmd = &cobra.Command{
@@ -11,13 +12,20 @@ var saveCmd = &cobra.Command{
 	Example: `  # Save the current ADC file for 'my-account'
   # Useful if you ran 'gcloud auth application-default login' manually
   gctx save my-account`,
-	Args:  cobra.ExactArgs(1),
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		m, err := manager.New()
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		adcPath, err := m.SaveCredentials(cmd.Context(), args[0])
 		if err != nil {
 			return err
 		}
 
-		return m.SaveCredentials(args[0])
+		fmt.Printf("ADC credentials saved for: %s\n", args[0])
+		fmt.Printf("Location: %s\n", adcPath)
+		return nil
 	},
 }
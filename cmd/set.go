@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/manager"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	setProtected    bool
+	setExpireAfter  time.Duration
+	setDefault      bool
+	setQuotaProject string
+)
+
+var setCmd = &cobra.Command{
+	Use:   "set <account-name> [section/property=value]...",
+	Short: "Change account settings and gcloud properties",
+	Long: `Change settings of an existing account.
+
+gcloud properties given as section/property=value are stored on the account,
+applied to its gcloud configuration and re-applied on every switch. An empty
+value (section/property=) removes the property.
+
+Protected accounts require typing the account name (or --yes in
+non-interactive mode) before switch or run use them. With --expire-after, a
+switch to a protected account reverts to the default account once the
+duration has passed.`,
+	Example: `  # Require confirmation before using 'prod'
+  gctx set prod --protected
+
+  # Revert to the default account 30 minutes after switching to 'prod'
+  gctx set dev --default
+  gctx set prod --expire-after 30m
+
+  # Remove protection
+  gctx set prod --protected=false
+
+  # Bill API usage through ADC to a dedicated project
+  gctx set work --quota-project billing-project
+
+  # Store gcloud properties on the account
+  gctx set work compute/region=europe-west1 compute/zone=europe-west1-b
+
+  # Remove a property
+  gctx set work compute/zone=`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		flags := cmd.Flags()
+		// Persistent flags such as --yes do not set anything
+		changed := 0
+		cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+			if f.Changed {
+				changed++
+			}
+		})
+		if changed == 0 && len(args) == 1 {
+			return fmt.Errorf("nothing to set, see 'gctx set --help'")
+		}
+
+		props, err := parseProperties(args[1:])
+		if err != nil {
+			return err
+		}
+
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		if len(props) > 0 {
+			if _, err := m.SetProperties(cmd.Context(), name, props); err != nil {
+				return err
+			}
+			for _, arg := range args[1:] {
+				fmt.Printf("Property: %s\n", arg)
+			}
+		}
+
+		if flags.Changed("protected") {
+			if err := m.SetProtected(name, setProtected); err != nil {
+				return err
+			}
+			fmt.Printf("Protected: %t\n", setProtected)
+		}
+
+		if flags.Changed("expire-after") {
+			if err := m.SetExpireAfter(name, setExpireAfter); err != nil {
+				return err
+			}
+			fmt.Printf("Expire after: %s\n", setExpireAfter)
+		}
+
+		if flags.Changed("quota-project") {
+			account, err := m.SetQuotaProject(cmd.Context(), name, setQuotaProject)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Quota project: %s\n", account.EffectiveQuotaProject())
+		}
+
+		if setDefault {
+			if err := m.SetDefaultAccount(name); err != nil {
+				return err
+			}
+			fmt.Printf("Default account: %s\n", name)
+		}
+
+		return nil
+	},
+}
+
+// parseProperties parses section/property=value arguments
+func parseProperties(args []string) (map[string]string, error) {
+	props := make(map[string]string, len(args))
+	for _, arg := range args {
+		property, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid property %q, expected section/property=value", arg)
+		}
+		if err := manager.ValidateProperty(property); err != nil {
+			return nil, err
+		}
+		props[property] = value
+	}
+	return props, nil
+}
+
+func init() {
+	setCmd.Flags().BoolVar(&setProtected, "protected", false,
+		"Require confirmation before using the account")
+	setCmd.Flags().DurationVar(&setExpireAfter, "expire-after", 0,
+		"Revert to the default account this long after switching (0 disables)")
+	setCmd.Flags().BoolVar(&setDefault, "default", false,
+		"Use this account as the safe default that expired switches revert to")
+	setCmd.Flags().StringVar(&setQuotaProject, "quota-project", "",
+		"Project billed for API usage through ADC (empty to use the account's project)")
+}
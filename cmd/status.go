@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the active account and any pending switch expiry",
+	Example: `  # Show the active account and the time left on a time-boxed switch
+  gctx status`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		info, err := m.PromptInfo("", "")
+		if err != nil {
+			return err
+		}
+		if info == nil {
+			fmt.Println("No active account")
+			return nil
+		}
+
+		account := info.Account
+		fmt.Printf("Active account: %s (%s)\n", account.Name, info.Project)
+		if account.Protected {
+			fmt.Printf("Protected:      yes%s\n", protectedMarker)
+		}
+		if info.NeedsLogin {
+			fmt.Printf("Credentials:    needs login (run: gctx login %s)\n", account.Name)
+		}
+
+		if expiry := m.ActiveExpiry(); expiry != nil {
+			remaining := time.Until(expiry.At).Round(time.Second)
+			fmt.Printf("Expires:        in %s, reverts to '%s' at %s\n",
+				remaining, expiry.RevertTo, expiry.At.Local().Format("15:04:05"))
+		}
+
+		return nil
+	},
+}
+
+var revertWait bool
+
+var revertExpiredCmd = &cobra.Command{
+	Use:    "revert-expired",
+	Short:  "Revert the active account if its switch has expired",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for {
+			// Reload every round: the user may have switched in the meantime
+			m, err := newManager(cmd)
+			if err != nil {
+				return err
+			}
+
+			expiry := m.ActiveExpiry()
+			if expiry == nil {
+				return nil
+			}
+
+			if wait := time.Until(expiry.At); wait > 0 {
+				if !revertWait {
+					return nil
+				}
+				select {
+				case <-time.After(wait):
+				case <-cmd.Context().Done():
+					return nil
+				}
+				continue
+			}
+
+			_, err = m.RevertExpired(cmd.Context())
+			return err
+		}
+	},
+}
+
+// startReverter launches a detached gctx that reverts the switch on expiry
+func startReverter() error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	child := exec.Command(self, "revert-expired", "--wait")
+	child.SysProcAttr = detachedProcAttr()
+	if err := child.Start(); err != nil {
+		return err
+	}
+	return child.Process.Release()
+}
+
+func init() {
+	revertExpiredCmd.Flags().BoolVar(&revertWait, "wait", false,
+		"Wait for the pending expiry instead of exiting")
+}
@@ -1,7 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/k0wl0n/gctx/pkg/manager"
+	"github.com/k0wl0n/gctx/pkg/selector"
 	"github.com/spf13/cobra"
 )
 
@@ -11,11 +16,23 @@ var switchCmd = &cobra.Command{
 	Example: `  # Switch to 'my-account'
   gctx switch my-account
 
-  # Switch interactively (fuzzy search)
-  gctx switch`,
+  # Switch back to the previously active account
+  gctx switch -
+
+  # Switch interactively (fuzzy search, most recently used first)
+  gctx switch
+
+  # Pick interactively among production accounts only
+  gctx switch --selector env=prod
+
+  # Switch for one hour, then revert to the current account
+  gctx switch break-glass --for 1h
+
+  # Same, with a background process reverting exactly on time
+  gctx switch break-glass --for 1h --background`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		m, err := manager.New()
+		m, err := newManager(cmd)
 		if err != nil {
 			return err
 		}
@@ -25,13 +42,72 @@ var switchCmd = &cobra.Command{
 			targetAccount = args[0]
 		} else {
 			// Interactive mode
-			selected, err := m.SelectAccountInteractive()
+			sel, err := selector.Parse(switchSelector)
+			if err != nil {
+				return err
+			}
+
+			selected, err := m.SelectAccountInteractive(sel)
 			if err != nil {
 				return err
 			}
 			targetAccount = selected
 		}
 
-		return m.SwitchAccount(targetAccount)
+		if switchFor <= 0 {
+			return switchTo(cmd.Context(), m, targetAccount)
+		}
+
+		account, err := m.SwitchAccountFor(cmd.Context(), targetAccount, switchFor)
+		if err != nil {
+			return err
+		}
+
+		expiry := m.ActiveExpiry()
+		fmt.Printf("Switched to account: %s (%s) until %s, then back to '%s'\n",
+			account.Name, account.ProjectID,
+			expiry.At.Local().Format("15:04:05"), expiry.RevertTo)
+
+		if switchBackground {
+			if err := startReverter(); err != nil {
+				return fmt.Errorf("failed to start background reverter: %w", err)
+			}
+		}
+		return nil
 	},
 }
+
+var (
+	switchFor        time.Duration
+	switchBackground bool
+	switchSelector   string
+)
+
+func init() {
+	switchCmd.Flags().DurationVar(&switchFor, "for", 0,
+		"Revert to the previous account after this duration (e.g. 1h)")
+	switchCmd.Flags().BoolVar(&switchBackground, "background", false,
+		"With --for, revert on time from a background process instead of on the next gctx invocation")
+	switchCmd.Flags().StringVarP(&switchSelector, "selector", "l", "",
+		selectorUsage+" (interactive mode)")
+}
+
+// switchTo switches to the named account and reports the result.
+// The name "-" selects the previously active account.
+func switchTo(ctx context.Context, m *manager.Manager, name string) error {
+	if name == "-" {
+		previous, err := m.PreviousAccount()
+		if err != nil {
+			return err
+		}
+		name = previous
+	}
+
+	account, err := m.SwitchAccount(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Switched to account: %s (%s)\n", account.Name, account.ProjectID)
+	return nil
+}
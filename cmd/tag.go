@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/k0wl0n/gctx/pkg/selector"
+	"github.com/spf13/cobra"
+)
+
+const selectorUsage = "Filter accounts by tags, e.g. env=prod,team!=data,client,!legacy"
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage account tags",
+	Long: `Manage free-form account tags such as env=prod or team=data.
+
+Tags can be matched with --selector on list, run and switch. A selector is a
+comma separated list of requirements that must all hold:
+
+  key=value   tag is set to value
+  key!=value  tag is not set to value (or not set at all)
+  key         tag is set
+  !key        tag is not set`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add <account-name> <key=value>...",
+	Short: "Add or update tags on an account",
+	Example: `  # Tag 'my-account' as a production data account
+  gctx tag add my-account env=prod team=data`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tags := make(map[string]string, len(args)-1)
+		for _, arg := range args[1:] {
+			key, value, err := selector.ParseTag(arg)
+			if err != nil {
+				return err
+			}
+			tags[key] = value
+		}
+
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		if err := m.AddTags(args[0], tags); err != nil {
+			return err
+		}
+
+		fmt.Printf("Tagged %s: %s\n", args[0], selector.FormatTags(tags))
+		return nil
+	},
+}
+
+var tagRmCmd = &cobra.Command{
+	Use:     "rm <account-name> <key>...",
+	Aliases: []string{"remove"},
+	Short:   "Remove tags from an account",
+	Example: `  # Remove the 'team' tag from 'my-account'
+  gctx tag rm my-account team`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, err := newManager(cmd)
+		if err != nil {
+			return err
+		}
+
+		if err := m.RemoveTags(args[0], args[1:]); err != nil {
+			return err
+		}
+
+		fmt.Printf("Removed tags from %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRmCmd)
+}
@@ -1,6 +1,7 @@
 package adc
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,6 +9,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type ADCCredential struct {
@@ -83,8 +85,9 @@ func SaveADC(accountName string) (string, error) {
 	return storagePath, nil
 }
 
-// RestoreADC copies saved ADC back to default location
-func RestoreADC(accountName string) error {
+// RestoreADC copies saved ADC back to default location. If ctx is cancelled
+// before the copy is moved into place, the default ADC is left untouched.
+func RestoreADC(ctx context.Context, accountName string) error {
 	storagePath := GetStoragePath(accountName)
 	if !fileExists(storagePath) {
 		return fmt.Errorf("no saved ADC for account: %s", accountName)
@@ -95,6 +98,11 @@ func RestoreADC(accountName string) error {
 	// Atomic write: temp file -> rename
 	tempPath := defaultPath + ".tmp"
 	if err := copyFile(storagePath, tempPath); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		os.Remove(tempPath)
 		return err
 	}
 
@@ -106,6 +114,117 @@ func RestoreADC(accountName string) error {
 	return nil
 }
 
+// MoveStorage renames an account's saved ADC file
+func MoveStorage(oldName, newName string) (string, error) {
+	oldPath := GetStoragePath(oldName)
+	newPath := GetStoragePath(newName)
+	if !fileExists(oldPath) {
+		return "", fmt.Errorf("no saved ADC for account: %s", oldName)
+	}
+	if fileExists(newPath) {
+		return "", fmt.Errorf("saved ADC already exists for account: %s", newName)
+	}
+
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return "", err
+	}
+	return newPath, nil
+}
+
+// CopyStorage copies an account's saved ADC file to another account
+func CopyStorage(srcName, dstName string) (string, error) {
+	srcPath := GetStoragePath(srcName)
+	if !fileExists(srcPath) {
+		return "", fmt.Errorf("no saved ADC for account: %s", srcName)
+	}
+
+	dstPath := GetStoragePath(dstName)
+	if err := copyFile(srcPath, dstPath); err != nil {
+		return "", err
+	}
+	return dstPath, nil
+}
+
+// WriteStorage stores ADC file content as an account's saved ADC, e.g. when
+// importing it from another machine
+func WriteStorage(accountName string, data []byte) (string, error) {
+	var cred ADCCredential
+	if err := json.Unmarshal(data, &cred); err != nil {
+		return "", fmt.Errorf("invalid ADC for account %s: %w", accountName, err)
+	}
+
+	path := GetStoragePath(accountName)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// SetQuotaProject rewrites the quota_project_id of an ADC file, keeping all
+// other fields intact
+func SetQuotaProject(path, projectID string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("invalid ADC file: %w", err)
+	}
+
+	if projectID == "" {
+		delete(fields, "quota_project_id")
+	} else {
+		fields["quota_project_id"] = projectID
+	}
+
+	data, err = json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Atomic write: temp file -> rename
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return nil
+}
+
+// RemoveTempFiles deletes temporary files left behind by an interrupted
+// atomic write of the default or a saved ADC
+func RemoveTempFiles() {
+	os.Remove(GetDefaultADCPath() + ".tmp")
+
+	pattern := GetStoragePath("*") + ".tmp"
+	matches, _ := filepath.Glob(pattern)
+	for _, path := range matches {
+		os.Remove(path)
+	}
+}
+
+// LoadADC reads an ADC file
+func LoadADC(path string) (*ADCCredential, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cred ADCCredential
+	if err := json.Unmarshal(data, &cred); err != nil {
+		return nil, fmt.Errorf("invalid ADC file: %w", err)
+	}
+	return &cred, nil
+}
+
 // ValidateADC checks if ADC JSON is valid
 func ValidateADC(path string) error {
 	data, err := os.ReadFile(path)
@@ -118,7 +237,7 @@ func ValidateADC(path string) error {
 }
 
 // GetADCEmail extracts email from ADC file
-func GetADCEmail(path string) (string, error) {
+func GetADCEmail(ctx context.Context, path string) (string, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return "", err
@@ -134,7 +253,8 @@ func GetADCEmail(path string) (string, error) {
 	}
 
 	// Try to get email from gcloud
-	cmd := exec.Command("gcloud", "config", "get-value", "account")
+	cmd := exec.CommandContext(ctx, "gcloud", "config", "get-value", "account")
+	cmd.WaitDelay = time.Second
 	output, err := cmd.Output()
 	if err != nil {
 		return "", nil
@@ -0,0 +1,205 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+)
+
+const (
+	// maxSize is the size at which the log is rotated
+	maxSize = 5 << 20
+
+	// maxBackups is the number of rotated logs kept (audit.jsonl.1 ...)
+	maxBackups = 3
+)
+
+// Actions recorded in the audit log
+const (
+	ActionCreate = "create"
+	ActionSave   = "save"
+	ActionSwitch = "switch"
+	ActionLogin  = "login"
+	ActionDelete = "delete"
+	ActionRun    = "run"
+	ActionExport = "export"
+	ActionImport = "import"
+)
+
+// Event records a single credential or account operation
+type Event struct {
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`
+	Account string    `json:"account"`
+	Project string    `json:"project,omitempty"`
+	// Fingerprint identifies the credentials involved without revealing them
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Hostname    string `json:"hostname"`
+	PID         int    `json:"pid"`
+	Command     string `json:"command,omitempty"`
+	// Args is the command path of a gcloud command run as the account;
+	// flags and their values are left out (see CommandPath)
+	Args  []string `json:"args,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+// CommandPath returns the leading arguments of a gcloud command up to the
+// first flag (e.g. "compute instances list"), so that flag values such as
+// tokens or impersonation targets never reach the log
+func CommandPath(args []string) []string {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			return args[:i]
+		}
+	}
+	return args
+}
+
+// GetAuditPath returns the location of the audit log
+func GetAuditPath() (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit.jsonl"), nil
+}
+
+// Append writes event to the log, filling in the time, hostname and pid,
+// and rotates the log once it exceeds maxSize
+func Append(event Event) error {
+	path, err := GetAuditPath()
+	if err != nil {
+		return err
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Hostname == "" {
+		event.Hostname, _ = os.Hostname()
+	}
+	if event.PID == 0 {
+		event.PID = os.Getpid()
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size()+int64(len(line)) > maxSize {
+		if err := rotate(path); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(line)
+	return err
+}
+
+// Load returns the events recorded since the given time (all if zero),
+// oldest first, including rotated logs
+func Load(since time.Time) ([]Event, error) {
+	path, err := GetAuditPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for i := maxBackups; i >= 0; i-- {
+		loaded, err := loadFile(backupPath(path, i), since)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, loaded...)
+	}
+	return events, nil
+}
+
+// Fingerprint returns a short hash identifying the credentials in an ADC
+// file: its refresh token, or the whole file for other credential types.
+// It returns "" if the file cannot be read.
+func Fingerprint(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	var cred struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if json.Unmarshal(data, &cred) == nil && cred.RefreshToken != "" {
+		data = []byte(cred.RefreshToken)
+	}
+
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+func loadFile(path string, since time.Time) ([]Event, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1<<20)
+	for n := 1; scanner.Scan(); n++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		if event.Time.Before(since) {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// rotate shifts audit.jsonl to audit.jsonl.1, .1 to .2 and so on, dropping
+// the oldest backup
+func rotate(path string) error {
+	os.Remove(backupPath(path, maxBackups))
+	for i := maxBackups - 1; i >= 0; i-- {
+		err := os.Rename(backupPath(path, i), backupPath(path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func backupPath(path string, n int) string {
+	if n == 0 {
+		return path
+	}
+	return fmt.Sprintf("%s.%d", path, n)
+}
@@ -0,0 +1,25 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommandPath(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{args: nil, want: nil},
+		{args: []string{"compute", "instances", "list"}, want: []string{"compute", "instances", "list"}},
+		{args: []string{"config", "list", "--impersonate-service-account=sa@p.iam"}, want: []string{"config", "list"}},
+		{args: []string{"auth", "print-access-token", "--account", "me@example.com", "extra"}, want: []string{"auth", "print-access-token"}},
+		{args: []string{"--project", "p", "projects", "list"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := CommandPath(tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("CommandPath(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package bundle
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+)
+
+// Version is the bundle payload format written by Seal
+const Version = 1
+
+const (
+	// magic identifies gctx bundles, followed by a format byte
+	magic       = "GCTXBNDL"
+	formatAES   = 1
+	saltSize    = 16
+	keySize     = 32
+	kdfRounds   = 600_000
+	headerSize  = len(magic) + 1 + saltSize
+	minPassword = 8
+)
+
+// ErrDecrypt means the passphrase is wrong or the bundle was altered
+var ErrDecrypt = errors.New("cannot decrypt bundle: wrong passphrase or corrupted file")
+
+// Bundle is the decrypted content of an export
+type Bundle struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Hostname  string    `json:"hostname,omitempty"`
+	Entries   []Entry   `json:"entries"`
+}
+
+// Entry is one exported account with its saved ADC file, if any
+type Entry struct {
+	Account *config.Account `json:"account"`
+	ADC     json.RawMessage `json:"adc,omitempty"`
+}
+
+// ValidatePassphrase rejects passphrases too short to protect credentials
+func ValidatePassphrase(passphrase string) error {
+	if len(passphrase) < minPassword {
+		return fmt.Errorf("passphrase must be at least %d characters", minPassword)
+	}
+	return nil
+}
+
+// Seal encrypts b with a key derived from passphrase (PBKDF2-SHA256,
+// AES-256-GCM). The header is authenticated along with the payload.
+func Seal(b *Bundle, passphrase string) ([]byte, error) {
+	if err := ValidatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, headerSize)
+	copy(header, magic)
+	header[len(magic)] = formatAES
+	if _, err := rand.Read(header[len(magic)+1:]); err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(passphrase, header[len(magic)+1:])
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := append(header, nonce...)
+	return aead.Seal(out, nonce, payload, header), nil
+}
+
+// Open decrypts a bundle sealed with passphrase
+func Open(data []byte, passphrase string) (*Bundle, error) {
+	if len(data) < headerSize || string(data[:len(magic)]) != magic {
+		return nil, fmt.Errorf("not a gctx bundle")
+	}
+	if data[len(magic)] != formatAES {
+		return nil, fmt.Errorf("unsupported bundle format %d", data[len(magic)])
+	}
+
+	header := data[:headerSize]
+	aead, err := newAEAD(passphrase, header[len(magic)+1:])
+	if err != nil {
+		return nil, err
+	}
+
+	rest := data[headerSize:]
+	if len(rest) < aead.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	nonce, ciphertext := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+
+	payload, err := aead.Open(nil, nonce, ciphertext, header)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+
+	var b Bundle
+	if err := json.Unmarshal(payload, &b); err != nil {
+		return nil, fmt.Errorf("invalid bundle payload: %w", err)
+	}
+	if b.Version > Version {
+		return nil, fmt.Errorf("bundle version %d is newer than supported (%d), upgrade gctx", b.Version, Version)
+	}
+	return &b, nil
+}
+
+// Write seals b into the file at path, readable by the owner only
+func Write(path string, b *Bundle, passphrase string) error {
+	data, err := Seal(b, passphrase)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Read opens the bundle file at path
+func Read(path, passphrase string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Open(data, passphrase)
+}
+
+func newAEAD(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, kdfRounds, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
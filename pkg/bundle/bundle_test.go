@@ -0,0 +1,140 @@
+package bundle
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+)
+
+const passphrase = "correct horse"
+
+func testBundle() *Bundle {
+	return &Bundle{
+		Version:   Version,
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Hostname:  "laptop",
+		Entries: []Entry{{
+			Account: &config.Account{Name: "work", ConfigName: "work-config", ProjectID: "p"},
+			ADC:     json.RawMessage(`{"type":"authorized_user","refresh_token":"r"}`),
+		}},
+	}
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	data, err := Seal(testBundle(), passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Open(data, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Hostname != "laptop" || !b.CreatedAt.Equal(testBundle().CreatedAt) || len(b.Entries) != 1 {
+		t.Fatalf("bundle = %+v", b)
+	}
+	entry := b.Entries[0]
+	if entry.Account.Name != "work" || entry.Account.ProjectID != "p" {
+		t.Errorf("account = %+v", entry.Account)
+	}
+	if string(entry.ADC) != `{"type":"authorized_user","refresh_token":"r"}` {
+		t.Errorf("ADC = %s", entry.ADC)
+	}
+}
+
+func TestSealUsesFreshSaltAndNonce(t *testing.T) {
+	first, err := Seal(testBundle(), passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Seal(testBundle(), passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(first) == string(second) {
+		t.Error("sealing twice produced the same output")
+	}
+}
+
+func TestOpenWrongPassphrase(t *testing.T) {
+	data, err := Seal(testBundle(), passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(data, "wrong passphrase"); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("err = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestOpenTampered(t *testing.T) {
+	data, err := Seal(testBundle(), passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		offset int
+	}{
+		{name: "salt", offset: len(magic) + 1},
+		{name: "nonce", offset: headerSize},
+		{name: "ciphertext", offset: headerSize + 12 + 1},
+		{name: "tag", offset: len(data) - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := append([]byte(nil), data...)
+			tampered[tt.offset] ^= 0x01
+			if _, err := Open(tampered, passphrase); !errors.Is(err, ErrDecrypt) {
+				t.Fatalf("err = %v, want ErrDecrypt", err)
+			}
+		})
+	}
+}
+
+func TestOpenInvalidHeader(t *testing.T) {
+	data, err := Seal(testBundle(), passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	badMagic := append([]byte(nil), data...)
+	badMagic[0] ^= 0x01
+	badFormat := append([]byte(nil), data...)
+	badFormat[len(magic)] = 99
+
+	for name, input := range map[string][]byte{
+		"magic":     badMagic,
+		"format":    badFormat,
+		"truncated": data[:headerSize-1],
+		"no nonce":  data[:headerSize+4],
+	} {
+		if _, err := Open(input, passphrase); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSealRejectsShortPassphrase(t *testing.T) {
+	if _, err := Seal(testBundle(), "short"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gctx.bundle")
+	if err := Write(path, testBundle(), passphrase); err != nil {
+		t.Fatal(err)
+	}
+	b, err := Read(path, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Entries) != 1 || b.Entries[0].Account.Name != "work" {
+		t.Errorf("bundle = %+v", b)
+	}
+}
@@ -11,6 +11,40 @@ import (
 type Config struct {
 	Accounts      map[string]*Account `json:"accounts"`
 	ActiveAccount string              `json:"active_account,omitempty"`
+	// DefaultAccount is the safe account to fall back to when a switch expires
+	DefaultAccount string `json:"default_account,omitempty"`
+	// ActiveExpiry, when set, reverts the active account once it has passed
+	ActiveExpiry *Expiry `json:"active_expiry,omitempty"`
+	// Endpoints overrides Google API endpoints, e.g. to use local stubs
+	Endpoints Endpoints `json:"endpoints,omitzero"`
+	// ProjectsCacheTTL is how long discovered projects are cached (default 1h)
+	ProjectsCacheTTL Duration `json:"projects_cache_ttl,omitempty"`
+	// Hooks run around operations on any account
+	Hooks Hooks `json:"hooks,omitempty"`
+	// HookTimeout bounds each hook (default 30s)
+	HookTimeout Duration `json:"hook_timeout,omitempty"`
+	// Registries maps container registry hosts to the account whose
+	// credentials the docker credential helper returns for them
+	Registries map[string]string `json:"registries,omitempty"`
+	// GitURLs maps git URL prefixes to the account whose credentials the
+	// git credential helper returns for them
+	GitURLs map[string]string `json:"git_urls,omitempty"`
+}
+
+// Hooks maps an event (e.g. "pre-switch", "post-login") to the shell
+// commands run on it
+type Hooks map[string][]string
+
+// Endpoints holds API endpoint overrides; empty values use Google's defaults
+type Endpoints struct {
+	Token           string `json:"token,omitempty"`
+	ResourceManager string `json:"resource_manager,omitempty"`
+}
+
+// Expiry describes when and where a time-boxed switch reverts
+type Expiry struct {
+	At       time.Time `json:"at"`
+	RevertTo string    `json:"revert_to"`
 }
 
 type Account struct {
@@ -20,8 +54,62 @@ type Account struct {
 	ADCPath    string    `json:"adc_path"`
 	CreatedAt  time.Time `json:"created_at"`
 	Email      string    `json:"email,omitempty"`
+	// Protected marks accounts (e.g. production) that deserve extra care
+	Protected bool `json:"protected,omitempty"`
+	// ExpireAfter reverts a switch to this protected account to the
+	// default account after the given duration
+	ExpireAfter Duration `json:"expire_after,omitempty"`
+	// CredentialStatus is the last known state of the stored credentials
+	CredentialStatus string `json:"credential_status,omitempty"`
+	// CredentialCheckedAt is when CredentialStatus was last determined
+	CredentialCheckedAt time.Time `json:"credential_checked_at,omitzero"`
+	// Tags are free-form labels (e.g. env=prod) used by selectors
+	Tags map[string]string `json:"tags,omitempty"`
+	// Projects are favourite projects offered when switching project;
+	// ProjectID is the one currently in use
+	Projects []string `json:"projects,omitempty"`
+	// QuotaProject is billed for API usage through ADC; when empty the
+	// account's project is used
+	QuotaProject string `json:"quota_project,omitempty"`
+	// Properties are extra gcloud properties (e.g. compute/region) applied
+	// to the account's gcloud configuration
+	Properties map[string]string `json:"properties,omitempty"`
+	// Hooks run around operations on this account, after the global ones
+	Hooks Hooks `json:"hooks,omitempty"`
+	// KubeContext is the kubectl context made current on switch
+	KubeContext string `json:"kube_context,omitempty"`
+}
+
+// EffectiveQuotaProject returns the project billed for API usage
+func (a *Account) EffectiveQuotaProject() string {
+	if a.QuotaProject != "" {
+		return a.QuotaProject
+	}
+	return a.ProjectID
+}
+
+// AllProjects returns the account's current project followed by its other
+// favourite projects
+func (a *Account) AllProjects() []string {
+	projects := []string{a.ProjectID}
+	for _, p := range a.Projects {
+		if p != a.ProjectID {
+			projects = append(projects, p)
+		}
+	}
+	return projects
 }
 
+// Credential states recorded in Account.CredentialStatus
+const (
+	CredentialUnknown    = ""
+	CredentialOK         = "ok"
+	CredentialNeedsLogin = "needs_login"
+	// CredentialError means the last check failed for another reason
+	// (e.g. network), so the credentials may still be valid
+	CredentialError = "error"
+)
+
 func GetConfigDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -113,10 +201,65 @@ func (c *Config) DeleteAccount(name string) error {
 	delete(c.Accounts, name)
 	if c.ActiveAccount == name {
 		c.ActiveAccount = ""
+		c.ActiveExpiry = nil
+	}
+	// The temporary switch can no longer revert to the deleted account
+	if c.ActiveExpiry != nil && c.ActiveExpiry.RevertTo == name {
+		c.ActiveExpiry = nil
+	}
+	if c.DefaultAccount == name {
+		c.DefaultAccount = ""
+	}
+	for host, account := range c.Registries {
+		if account == name {
+			delete(c.Registries, host)
+		}
+	}
+	for prefix, account := range c.GitURLs {
+		if account == name {
+			delete(c.GitURLs, prefix)
+		}
 	}
 	return c.Save()
 }
 
+// RenameAccount moves an account to a new name, updating every reference
+func (c *Config) RenameAccount(oldName, newName string) error {
+	account, exists := c.Accounts[oldName]
+	if !exists {
+		return fmt.Errorf("account '%s' not found", oldName)
+	}
+	if _, exists := c.Accounts[newName]; exists {
+		return fmt.Errorf("account '%s' already exists", newName)
+	}
+
+	delete(c.Accounts, oldName)
+	account.Name = newName
+	c.Accounts[newName] = account
+
+	if c.ActiveAccount == oldName {
+		c.ActiveAccount = newName
+	}
+	if c.DefaultAccount == oldName {
+		c.DefaultAccount = newName
+	}
+	if c.ActiveExpiry != nil && c.ActiveExpiry.RevertTo == oldName {
+		c.ActiveExpiry.RevertTo = newName
+	}
+	for host, account := range c.Registries {
+		if account == oldName {
+			c.Registries[host] = newName
+		}
+	}
+	for prefix, account := range c.GitURLs {
+		if account == oldName {
+			c.GitURLs[prefix] = newName
+		}
+	}
+
+	return c.Save()
+}
+
 func (c *Config) ListAccounts() []*Account {
 	accounts := make([]*Account, 0, len(c.Accounts))
 	for _, acc := range c.Accounts {
@@ -132,3 +275,23 @@ func (c *Config) SetActive(name string) error {
 	c.ActiveAccount = name
 	return c.Save()
 }
+
+// Duration is a time.Duration stored as a human readable string (e.g. "30m")
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
@@ -0,0 +1,74 @@
+package dirconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// File names looked up in each directory, in order of precedence
+var FileNames = []string{".gctx", ".gctx.yaml"}
+
+// File is a per-directory account selection. The file either contains just
+// an account name, or a YAML mapping:
+//
+//	account: work
+//	project: work-staging
+type File struct {
+	Path    string `yaml:"-"`
+	Account string `yaml:"account"`
+	Project string `yaml:"project,omitempty"`
+}
+
+// Find walks up from dir and returns the first directory file found, or nil
+// if there is none
+func Find(dir string) (*File, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		for _, name := range FileNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return Parse(path)
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// Parse reads a directory file
+func Parse(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File{Path: path}
+	if err := yaml.Unmarshal(data, file); err != nil {
+		// Not a mapping: the whole file is the account name
+		var name string
+		if err := yaml.Unmarshal(data, &name); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", path, err)
+		}
+		file.Account = name
+	}
+
+	file.Account = strings.TrimSpace(file.Account)
+	file.Project = strings.TrimSpace(file.Project)
+	if file.Account == "" {
+		return nil, fmt.Errorf("%s does not name an account", path)
+	}
+
+	return file, nil
+}
@@ -0,0 +1,66 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// GetConfigPath returns docker's client configuration file, honouring
+// DOCKER_CONFIG
+func GetConfigPath() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// SetCredHelpers registers helper (e.g. "gctx" for docker-credential-gctx)
+// for the given registry hosts in docker's configuration. An empty helper
+// removes the hosts instead. Other settings are left untouched.
+func SetCredHelpers(hosts []string, helper string) error {
+	path := GetConfigPath()
+
+	fields := map[string]json.RawMessage{}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return fmt.Errorf("invalid docker config %s: %w", path, err)
+		}
+	}
+
+	helpers := map[string]string{}
+	if raw, ok := fields["credHelpers"]; ok {
+		if err := json.Unmarshal(raw, &helpers); err != nil {
+			return fmt.Errorf("invalid credHelpers in %s: %w", path, err)
+		}
+	}
+	for _, host := range hosts {
+		if helper == "" {
+			delete(helpers, host)
+		} else {
+			helpers[host] = helper
+		}
+	}
+
+	raw, err := json.Marshal(helpers)
+	if err != nil {
+		return err
+	}
+	fields["credHelpers"] = raw
+
+	data, err = json.MarshalIndent(fields, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
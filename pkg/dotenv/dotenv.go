@@ -0,0 +1,90 @@
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Format renders env as KEY=value lines, sorted by key
+func Format(env map[string]string) string {
+	var b strings.Builder
+	for _, key := range sortedKeys(env) {
+		fmt.Fprintf(&b, "%s=%s\n", key, quote(env[key]))
+	}
+	return b.String()
+}
+
+// Update writes env into the .env file at path: existing assignments of the
+// same variables are replaced in place, new ones are appended, and every
+// other line (comments, unrelated variables) is kept.
+func Update(path string, env map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	}
+
+	written := make(map[string]bool, len(env))
+	for i, line := range lines {
+		prefix, key, ok := assignment(line)
+		if !ok {
+			continue
+		}
+		if value, ok := env[key]; ok {
+			lines[i] = prefix + key + "=" + quote(value)
+			written[key] = true
+		}
+	}
+	for _, key := range sortedKeys(env) {
+		if !written[key] {
+			lines = append(lines, key+"="+quote(env[key]))
+		}
+	}
+
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600)
+}
+
+// assignment returns the variable a line assigns and what precedes it
+// (indentation and an optional "export "), kept when the line is rewritten
+func assignment(line string) (prefix, key string, ok bool) {
+	rest := strings.TrimLeft(line, " \t")
+	if rest == "" || strings.HasPrefix(rest, "#") {
+		return "", "", false
+	}
+	if after, found := strings.CutPrefix(rest, "export"); found && strings.IndexAny(after, " \t") == 0 {
+		rest = strings.TrimLeft(after, " \t")
+	}
+	prefix = line[:len(line)-len(rest)]
+
+	key, _, ok = strings.Cut(rest, "=")
+	key = strings.TrimSpace(key)
+	return prefix, key, ok && key != ""
+}
+
+// quote double-quotes values that a .env parser would otherwise split or
+// interpret
+func quote(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n#\"'\\$`=") {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	value = strings.ReplaceAll(value, "$", `\$`)
+	return `"` + value + `"`
+}
+
+func sortedKeys(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
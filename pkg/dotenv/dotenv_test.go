@@ -0,0 +1,111 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{in: "plain", want: "plain"},
+		{in: "/path/to/adc.json", want: "/path/to/adc.json"},
+		{in: "", want: `""`},
+		{in: "two words", want: `"two words"`},
+		{in: "tab\there", want: "\"tab\there\""},
+		{in: "a#comment", want: `"a#comment"`},
+		{in: `say "hi"`, want: `"say \"hi\""`},
+		{in: "it's", want: `"it's"`},
+		{in: `back\slash`, want: `"back\\slash"`},
+		{in: "line1\nline2", want: `"line1\nline2"`},
+		{in: "$HOME", want: `"\$HOME"`},
+		{in: "a=b", want: `"a=b"`},
+	}
+
+	for _, tt := range tests {
+		if got := quote(tt.in); got != tt.want {
+			t.Errorf("quote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	got := Format(map[string]string{"B": "two words", "A": "1"})
+	if want := "A=1\nB=\"two words\"\n"; got != want {
+		t.Errorf("Format = %q, want %q", got, want)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		env      map[string]string
+		want     string
+	}{
+		{
+			name: "new file",
+			env:  map[string]string{"B": "2", "A": "1"},
+			want: "A=1\nB=2\n",
+		},
+		{
+			name:     "replace existing key",
+			existing: "A=old\nB=keep\n",
+			env:      map[string]string{"A": "new"},
+			want:     "A=new\nB=keep\n",
+		},
+		{
+			name:     "keep export prefix",
+			existing: "export A=old\n  export\tB=old\n",
+			env:      map[string]string{"A": "new", "B": "two words"},
+			want:     "export A=new\n  export\tB=\"two words\"\n",
+		},
+		{
+			name:     "keep comments and unrelated lines",
+			existing: "# project settings\nOTHER=1\n\n# A=commented\nA = old\nexporter=x\n",
+			env:      map[string]string{"A": "new"},
+			want:     "# project settings\nOTHER=1\n\n# A=commented\nA=new\nexporter=x\n",
+		},
+		{
+			name:     "append new keys sorted",
+			existing: "OTHER=1",
+			env:      map[string]string{"Z": "z", "A": "a"},
+			want:     "OTHER=1\nA=a\nZ=z\n",
+		},
+		{
+			name:     "quote special values",
+			existing: "A=old\n",
+			env: map[string]string{
+				"A": "a # b",
+				"B": `say "hi"`,
+				"C": "line1\nline2",
+			},
+			want: "A=\"a # b\"\nB=\"say \\\"hi\\\"\"\nC=\"line1\\nline2\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".env")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := Update(path, tt.env); err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(data); got != tt.want {
+				t.Errorf("Update wrote:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
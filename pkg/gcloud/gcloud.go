@@ -1,17 +1,31 @@
 package gcloud
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"time"
 )
 
+// waitDelay bounds how long a killed gcloud may keep its output pipes open
+// through its own children
+const waitDelay = time.Second
+
+// command prepares a gcloud invocation that is killed once ctx is done
+func command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "gcloud", args...)
+	cmd.WaitDelay = waitDelay
+	return cmd
+}
+
 // CreateConfig creates a new gcloud configuration
-func CreateConfig(configName string) error {
-	cmd := exec.Command("gcloud", "config", "configurations",
+func CreateConfig(ctx context.Context, configName string) error {
+	cmd := command(ctx, "config", "configurations",
 		"create", configName)
 	output, err := cmd.CombinedOutput()
 
@@ -24,15 +38,15 @@ func CreateConfig(configName string) error {
 }
 
 // ActivateConfig activates a gcloud configuration
-func ActivateConfig(configName string) error {
-	cmd := exec.Command("gcloud", "config", "configurations",
+func ActivateConfig(ctx context.Context, configName string) error {
+	cmd := command(ctx, "config", "configurations",
 		"activate", configName)
 	return cmd.Run()
 }
 
 // SetProject sets the project for current configuration
-func SetProject(projectID string) error {
-	cmd := exec.Command("gcloud", "config", "set", "project", projectID)
+func SetProject(ctx context.Context, projectID string) error {
+	cmd := command(ctx, "config", "set", "project", projectID)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("%w\n%s", err, string(output))
@@ -41,8 +55,8 @@ func SetProject(projectID string) error {
 }
 
 // AuthLogin runs gcloud auth login interactively
-func AuthLogin() error {
-	cmd := exec.Command("gcloud", "auth", "login")
+func AuthLogin(ctx context.Context) error {
+	cmd := command(ctx, "auth", "login")
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -50,41 +64,60 @@ func AuthLogin() error {
 }
 
 // AuthADCLogin runs gcloud auth application-default login
-func AuthADCLogin() ([]string, error) {
-	cmd := exec.Command("gcloud", "auth", "application-default", "login")
+func AuthADCLogin(ctx context.Context) ([]string, error) {
+	cmd := command(ctx, "auth", "application-default", "login")
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 
-	// Capture stderr for warnings
-	stderrPipe, err := cmd.StderrPipe()
-	if err != nil {
-		return nil, err
-	}
-
-	if err := cmd.Start(); err != nil {
-		return nil, err
-	}
-
-	// Read stderr
-	stderr, _ := io.ReadAll(stderrPipe)
-	warnings := parseWarnings(string(stderr))
+	// Capture stderr for warnings. A buffer rather than a pipe, so that
+	// WaitDelay applies if gcloud is killed.
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
 
-	err = cmd.Wait()
-	return warnings, err
+	err := cmd.Run()
+	return parseWarnings(stderr.String()), err
 }
 
 // RunCommand runs arbitrary gcloud command
-func RunCommand(args ...string) error {
-	cmd := exec.Command("gcloud", args...)
+func RunCommand(ctx context.Context, args ...string) error {
+	cmd := command(ctx, args...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
+// GetActiveConfigPath returns the file in which gcloud records the name of
+// the active configuration
+func GetActiveConfigPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "gcloud", "active_config")
+}
+
+// ReadActiveConfig returns the active configuration name recorded by gcloud
+// without running it
+func ReadActiveConfig() (string, error) {
+	data, err := os.ReadFile(GetActiveConfigPath())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// GetActiveConfig returns the name of the active gcloud configuration
+func GetActiveConfig(ctx context.Context) (string, error) {
+	cmd := command(ctx, "config", "configurations", "list",
+		"--filter=is_active=true", "--format=value(name)")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get active config: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // ListConfigs returns all gcloud configurations
-func ListConfigs() ([]string, error) {
-	cmd := exec.Command("gcloud", "config", "configurations",
+func ListConfigs(ctx context.Context) ([]string, error) {
+	cmd := command(ctx, "config", "configurations",
 		"list", "--format=json")
 	output, err := cmd.Output()
 	if err != nil {
@@ -107,6 +140,84 @@ func ListConfigs() ([]string, error) {
 	return names, nil
 }
 
+// DeleteConfig deletes a gcloud configuration
+func DeleteConfig(ctx context.Context, configName string) error {
+	cmd := command(ctx, "config", "configurations",
+		"delete", configName, "--quiet")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to delete config: %w\n%s", err, output)
+	}
+	return nil
+}
+
+// GetConfigProperties returns the properties of a gcloud configuration
+// keyed by "section/name" (e.g. "compute/region")
+func GetConfigProperties(ctx context.Context, configName string) (map[string]string, error) {
+	cmd := command(ctx, "config", "configurations",
+		"describe", configName, "--format=json")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe config %s: %w", configName, err)
+	}
+
+	var described struct {
+		Properties map[string]map[string]string `json:"properties"`
+	}
+	if err := json.Unmarshal(output, &described); err != nil {
+		return nil, err
+	}
+
+	props := make(map[string]string)
+	for section, values := range described.Properties {
+		for name, value := range values {
+			props[section+"/"+name] = value
+		}
+	}
+	return props, nil
+}
+
+// SetConfigProperty sets a property on a specific gcloud configuration
+func SetConfigProperty(ctx context.Context, configName, property, value string) error {
+	cmd := command(ctx, "config", "set", property, value,
+		"--configuration", configName)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, string(output))
+	}
+	return nil
+}
+
+// UnsetConfigProperty removes a property from a specific gcloud configuration
+func UnsetConfigProperty(ctx context.Context, configName, property string) error {
+	cmd := command(ctx, "config", "unset", property,
+		"--configuration", configName)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w\n%s", err, string(output))
+	}
+	return nil
+}
+
+// CopyConfig creates dst with all properties of src
+func CopyConfig(ctx context.Context, src, dst string) error {
+	props, err := GetConfigProperties(ctx, src)
+	if err != nil {
+		return err
+	}
+
+	if err := CreateConfig(ctx, dst); err != nil {
+		return err
+	}
+
+	for property, value := range props {
+		if err := SetConfigProperty(ctx, dst, property, value); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", property, err)
+		}
+	}
+	return nil
+}
+
 func parseWarnings(stderr string) []string {
 	var warnings []string
 	for _, line := range strings.Split(stderr, "\n") {
@@ -0,0 +1,44 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// ConfigureHelper registers helper (e.g. "gctx" for git-credential-gctx) in
+// the global git configuration for URLs under urlPrefix, sending the full
+// repository path so that links to individual repositories match
+func ConfigureHelper(ctx context.Context, urlPrefix, helper string) error {
+	if err := run(ctx, "config", "--global", "credential."+urlPrefix+".helper", helper); err != nil {
+		return err
+	}
+	return run(ctx, "config", "--global", "credential."+urlPrefix+".useHttpPath", "true")
+}
+
+// UnconfigureHelper removes what ConfigureHelper set for urlPrefix
+func UnconfigureHelper(ctx context.Context, urlPrefix string) error {
+	for _, key := range []string{"helper", "useHttpPath"} {
+		err := run(ctx, "config", "--global", "--unset-all", "credential."+urlPrefix+"."+key)
+
+		// Exit status 5: the key was not set
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 5 {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func run(ctx context.Context, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git %s: %w\n%s", args[len(args)-2], err, output)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+)
+
+// maxEntries caps the number of switches kept on disk
+const maxEntries = 500
+
+// Entry records a single account switch
+type Entry struct {
+	Time   time.Time `json:"time"`
+	From   string    `json:"from,omitempty"`
+	To     string    `json:"to"`
+	Source string    `json:"source,omitempty"`
+}
+
+// GetHistoryPath returns the location of the switch history file
+func GetHistoryPath() (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.json"), nil
+}
+
+// Load returns all recorded switches, oldest first
+func Load() ([]Entry, error) {
+	path, err := GetHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Append records a switch, dropping the oldest entries beyond maxEntries
+func Append(entry Entry) error {
+	entries, err := Load()
+	if err != nil {
+		return err
+	}
+
+	entries = append(entries, entry)
+	if len(entries) > maxEntries {
+		entries = entries[len(entries)-maxEntries:]
+	}
+
+	return save(entries)
+}
+
+// Rename rewrites the switches to and from an account after it was renamed,
+// so that the previous account keeps resolving
+func Rename(oldName, newName string) error {
+	entries, err := Load()
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for i := range entries {
+		if entries[i].From == oldName {
+			entries[i].From = newName
+			changed = true
+		}
+		if entries[i].To == oldName {
+			entries[i].To = newName
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return save(entries)
+}
+
+func save(entries []Entry) error {
+	path, err := GetHistoryPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write a temporary file and rename it so that a concurrent reader
+	// never sees a partial history. The history reveals which accounts are
+	// used and when, so it is private to the user.
+	tmp, err := os.CreateTemp(filepath.Dir(path), "history-*.json.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Previous returns the account that was active before current, or "" if
+// the history has no such switch
+func Previous(entries []Entry, current string) string {
+	for i := len(entries) - 1; i >= 0; i-- {
+		from := entries[i].From
+		if from != "" && from != current {
+			return from
+		}
+	}
+	return ""
+}
+
+// LastUsed returns the most recent time each account was switched to
+func LastUsed(entries []Entry) map[string]time.Time {
+	used := make(map[string]time.Time, len(entries))
+	for _, e := range entries {
+		if e.Time.After(used[e.To]) {
+			used[e.To] = e.Time
+		}
+	}
+	return used
+}
@@ -0,0 +1,41 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// DefaultTimeout bounds a hook when no timeout is configured
+const DefaultTimeout = 30 * time.Second
+
+// Run runs command through the shell with env added to the environment and
+// input on stdin. Its standard output goes to out and its standard error to
+// the terminal. The hook is killed once timeout has elapsed or ctx is done.
+func Run(ctx context.Context, command string, env map[string]string, input []byte, out io.Writer, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := shell(ctx, command)
+	cmd.Env = os.Environ()
+	for key, value := range env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
@@ -0,0 +1,270 @@
+package kube
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// AuthPlugin is the kubectl credential plugin for GKE clusters
+const AuthPlugin = "gke-gcloud-auth-plugin"
+
+// Paths returns the kubeconfig files in use: those listed in KUBECONFIG,
+// or ~/.kube/config
+func Paths() []string {
+	var paths []string
+	for _, path := range filepath.SplitList(os.Getenv("KUBECONFIG")) {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) > 0 {
+		return paths
+	}
+
+	home, _ := os.UserHomeDir()
+	return []string{filepath.Join(home, ".kube", "config")}
+}
+
+// File is a kubeconfig file. It is edited as a YAML tree so that comments,
+// ordering and fields gctx does not know about are preserved.
+type File struct {
+	Path string
+	root *yaml.Node
+}
+
+// Load reads the kubeconfig at path; a missing file loads as empty
+func Load(path string) (*File, error) {
+	f := &File{Path: path, root: &yaml.Node{Kind: yaml.MappingNode}}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig %s: %w", path, err)
+	}
+	if len(doc.Content) > 0 {
+		if doc.Content[0].Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("invalid kubeconfig %s: not a mapping", path)
+		}
+		f.root = doc.Content[0]
+	}
+	return f, nil
+}
+
+// Save writes the file back. It writes a temporary file next to it and
+// renames it into place, so that an interrupted save never leaves a
+// truncated kubeconfig.
+func (f *File) Save() error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	// kubectl writes sequences without extra indentation
+	enc.CompactSeqIndent()
+	if err := enc.Encode(f.root); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	// Replace the target of a symlinked kubeconfig, not the link
+	path := f.Path
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".kubeconfig-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// CurrentContext returns the current-context set in this file, if any
+func (f *File) CurrentContext() string {
+	if node := lookup(f.root, "current-context"); node != nil {
+		return node.Value
+	}
+	return ""
+}
+
+// SetCurrentContext sets current-context
+func (f *File) SetCurrentContext(name string) {
+	set(f.root, "current-context", &yaml.Node{Kind: yaml.ScalarNode, Value: name})
+}
+
+// HasContext reports whether the file defines the named context
+func (f *File) HasContext(name string) bool {
+	return f.namedEntry("contexts", name) != nil
+}
+
+// SetContextUser points the named context at a user entry
+func (f *File) SetContextUser(contextName, user string) error {
+	entry := f.namedEntry("contexts", contextName)
+	if entry == nil {
+		return fmt.Errorf("context '%s' not found in %s", contextName, f.Path)
+	}
+
+	context := lookup(entry, "context")
+	if context == nil || context.Kind != yaml.MappingNode {
+		context = &yaml.Node{Kind: yaml.MappingNode}
+		set(entry, "context", context)
+	}
+	set(context, "user", &yaml.Node{Kind: yaml.ScalarNode, Value: user})
+	return nil
+}
+
+// SetAuthPluginUser adds or replaces a user entry authenticating through
+// the GKE auth plugin with the given gcloud configuration
+func (f *File) SetAuthPluginUser(name, gcloudConfig string) error {
+	var user yaml.Node
+	err := user.Encode(map[string]any{
+		"name": name,
+		"user": map[string]any{
+			"exec": map[string]any{
+				"apiVersion":         "client.authentication.k8s.io/v1beta1",
+				"command":            AuthPlugin,
+				"provideClusterInfo": true,
+				"installHint": "Install gke-gcloud-auth-plugin for use with kubectl by following " +
+					"https://cloud.google.com/kubernetes-engine/docs/how-to/cluster-access-for-kubectl#install_plugin",
+				"env": []map[string]string{
+					{"name": "CLOUDSDK_ACTIVE_CONFIG_NAME", "value": gcloudConfig},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	users := lookup(f.root, "users")
+	if users == nil || users.Kind != yaml.SequenceNode {
+		users = &yaml.Node{Kind: yaml.SequenceNode}
+		set(f.root, "users", users)
+	}
+	for i, entry := range users.Content {
+		if n := lookup(entry, "name"); n != nil && n.Value == name {
+			users.Content[i] = &user
+			return nil
+		}
+	}
+	users.Content = append(users.Content, &user)
+	return nil
+}
+
+// namedEntry returns the entry called name in a list such as contexts
+func (f *File) namedEntry(list, name string) *yaml.Node {
+	entries := lookup(f.root, list)
+	if entries == nil || entries.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, entry := range entries.Content {
+		if n := lookup(entry, "name"); n != nil && n.Value == name {
+			return entry
+		}
+	}
+	return nil
+}
+
+// FindContext returns the first kubeconfig file defining the named context
+func FindContext(name string) (*File, error) {
+	for _, path := range Paths() {
+		f, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		if f.HasContext(name) {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("kubectl context '%s' not found", name)
+}
+
+// UseContext makes name the current context. Like kubectl, it writes to the
+// first file that already sets current-context, or the first file.
+func UseContext(name string) error {
+	if _, err := FindContext(name); err != nil {
+		return err
+	}
+
+	paths := Paths()
+	var target *File
+	for _, path := range paths {
+		f, err := Load(path)
+		if err != nil {
+			return err
+		}
+		if f.CurrentContext() != "" {
+			target = f
+			break
+		}
+	}
+	if target == nil {
+		f, err := Load(paths[0])
+		if err != nil {
+			return err
+		}
+		target = f
+	}
+
+	if target.CurrentContext() == name {
+		return nil
+	}
+	target.SetCurrentContext(name)
+	return target.Save()
+}
+
+// lookup returns the value of key in a mapping node
+func lookup(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// set replaces or appends key in a mapping node
+func set(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
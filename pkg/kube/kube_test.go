@@ -0,0 +1,227 @@
+package kube
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const kubeconfig = `# managed by hand
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://prod.example.com
+  name: prod
+- cluster:
+    server: https://dev.example.com
+  name: dev
+contexts:
+- context:
+    cluster: prod
+    user: prod-user # keep me
+  name: prod
+- context:
+    cluster: dev
+    user: dev-user
+  name: dev
+current-context: dev
+preferences: {}
+users:
+- name: prod-user
+  user:
+    token: prod-token
+- name: dev-user
+  user:
+    token: dev-token
+`
+
+// writeKubeconfig writes kubeconfig to a temporary file and points
+// KUBECONFIG at it
+func writeKubeconfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(kubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", path)
+	return path
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// assertPreserved checks that the entries and comments gctx does not edit
+// survived a save
+func assertPreserved(t *testing.T, got string) {
+	t.Helper()
+	for _, want := range []string{
+		"# managed by hand",
+		"user: prod-user # keep me",
+		"server: https://prod.example.com",
+		"server: https://dev.example.com",
+		"token: prod-token",
+		"token: dev-token",
+		"preferences: {}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("saved kubeconfig lost %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestUseContext(t *testing.T) {
+	path := writeKubeconfig(t)
+
+	if err := UseContext("prod"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readFile(t, path)
+	if !strings.Contains(got, "current-context: prod\n") {
+		t.Errorf("current-context not updated:\n%s", got)
+	}
+	assertPreserved(t, got)
+
+	f, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.HasContext("dev") || !f.HasContext("prod") {
+		t.Errorf("contexts lost after save:\n%s", got)
+	}
+}
+
+func TestUseContextUnknown(t *testing.T) {
+	path := writeKubeconfig(t)
+
+	if err := UseContext("staging"); err == nil {
+		t.Fatal("UseContext(staging) succeeded, want error")
+	}
+	if got := readFile(t, path); got != kubeconfig {
+		t.Errorf("kubeconfig changed after a failed UseContext:\n%s", got)
+	}
+}
+
+func TestSetAuthPluginUser(t *testing.T) {
+	path := writeKubeconfig(t)
+
+	f, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetAuthPluginUser("gctx-work", "work-config"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetContextUser("prod", "gctx-work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replacing the user keeps a single entry
+	f, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetAuthPluginUser("gctx-work", "work2-config"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readFile(t, path)
+	for _, want := range []string{
+		"user: gctx-work",
+		"command: " + AuthPlugin,
+		"value: work2-config",
+		"current-context: dev",
+		"# managed by hand",
+		"server: https://prod.example.com",
+		"token: prod-token",
+		"token: dev-token",
+		"user: dev-user",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("saved kubeconfig is missing %q:\n%s", want, got)
+		}
+	}
+	if n := strings.Count(got, "name: gctx-work"); n != 1 {
+		t.Errorf("found %d gctx-work users, want 1:\n%s", n, got)
+	}
+	if strings.Contains(got, "work-config\n") {
+		t.Errorf("old auth plugin user kept:\n%s", got)
+	}
+}
+
+func TestSetContextUserUnknown(t *testing.T) {
+	path := writeKubeconfig(t)
+
+	f, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetContextUser("staging", "gctx-work"); err == nil {
+		t.Fatal("SetContextUser(staging) succeeded, want error")
+	}
+}
+
+func TestSaveKeepsModeAndLeavesNoTempFiles(t *testing.T) {
+	path := writeKubeconfig(t)
+	if err := os.Chmod(path, 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UseContext("prod"); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0640 {
+		t.Errorf("mode = %o, want 640", mode)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("directory contains %v, want only the kubeconfig", names)
+	}
+}
+
+func TestSaveFollowsSymlink(t *testing.T) {
+	target := writeKubeconfig(t)
+	link := filepath.Join(t.TempDir(), "config")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	t.Setenv("KUBECONFIG", link)
+
+	if err := UseContext("prod"); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("kubeconfig symlink replaced by a file")
+	}
+	if got := readFile(t, target); !strings.Contains(got, "current-context: prod\n") {
+		t.Errorf("symlink target not updated:\n%s", got)
+	}
+}
@@ -0,0 +1,30 @@
+package manager
+
+import (
+	"github.com/k0wl0n/gctx/pkg/adc"
+	"github.com/k0wl0n/gctx/pkg/audit"
+	"github.com/k0wl0n/gctx/pkg/config"
+)
+
+// auditEvent describes an operation on account, fingerprinting its saved
+// credentials as they are now
+func (m *Manager) auditEvent(action string, account *config.Account) audit.Event {
+	return audit.Event{
+		Action:      action,
+		Account:     account.Name,
+		Project:     account.ProjectID,
+		Fingerprint: audit.Fingerprint(adc.GetStoragePath(account.Name)),
+		Command:     m.command,
+	}
+}
+
+// record appends event to the audit log with the operation's outcome.
+// A log that cannot be written is reported but never fails the operation.
+func (m *Manager) record(event audit.Event, opErr error) {
+	if opErr != nil {
+		event.Error = opErr.Error()
+	}
+	if err := audit.Append(event); err != nil {
+		m.reporter.Warnf("failed to write audit log: %v", err)
+	}
+}
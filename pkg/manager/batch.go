@@ -0,0 +1,70 @@
+package manager
+
+import (
+	"context"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+)
+
+// BatchLoginResult is the outcome of logging in one account of a batch
+type BatchLoginResult struct {
+	Account string
+	Login   *LoginResult
+	Err     error
+}
+
+// AccountsNeedingLogin checks the credentials of the named accounts and
+// returns those that need a login, in the order given
+func (m *Manager) AccountsNeedingLogin(ctx context.Context, names []string) ([]string, error) {
+	results, err := m.CheckCredentials(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []string
+	for _, r := range results {
+		if r.Status == config.CredentialNeedsLogin {
+			expired = append(expired, r.Account)
+		} else if r.Status == config.CredentialError {
+			m.reporter.Warnf("skipping '%s': could not check credentials: %v", r.Account, r.Err)
+		}
+	}
+	return expired, nil
+}
+
+// LoginAccounts runs the authentication flow for each account in turn,
+// continuing past failures, and switches back to the originally active
+// account at the end. Once ctx is cancelled, the remaining accounts are
+// skipped.
+func (m *Manager) LoginAccounts(ctx context.Context, names []string) []BatchLoginResult {
+	original := m.config.ActiveAccount
+	defer func() {
+		if original == "" || original == m.config.ActiveAccount {
+			return
+		}
+		// Switch back even after Ctrl-C, like a rollback
+		restoreCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+		defer cancel()
+		if _, err := m.switchAccount(restoreCtx, original, true); err != nil {
+			m.reporter.Warnf("failed to switch back to '%s': %v", original, err)
+			return
+		}
+		m.reporter.Infof("Switched back to account: %s", original)
+	}()
+
+	results := make([]BatchLoginResult, 0, len(names))
+	for i, name := range names {
+		if ctx.Err() != nil {
+			break
+		}
+		m.reporter.Infof("[%d/%d] Logging in: %s", i+1, len(names), name)
+
+		login, err := m.Login(ctx, name)
+		if err != nil {
+			m.reporter.Warnf("login for '%s' failed: %v", name, err)
+		}
+		results = append(results, BatchLoginResult{Account: name, Login: login, Err: err})
+	}
+
+	return results
+}
@@ -0,0 +1,290 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/adc"
+	"github.com/k0wl0n/gctx/pkg/audit"
+	"github.com/k0wl0n/gctx/pkg/bundle"
+	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/gcloud"
+	"github.com/k0wl0n/gctx/pkg/kube"
+)
+
+// Ways of importing an account whose name is already taken
+const (
+	ConflictSkip      = "skip"
+	ConflictOverwrite = "overwrite"
+	ConflictRename    = "rename"
+)
+
+// ConflictModes lists the accepted conflict handling modes
+var ConflictModes = []string{ConflictSkip, ConflictOverwrite, ConflictRename}
+
+// Outcomes of importing a bundle entry
+const (
+	ImportAdded       = "added"
+	ImportOverwritten = "overwritten"
+	ImportRenamed     = "renamed"
+	ImportSkipped     = "skipped"
+)
+
+// ImportResult describes what happened to one bundle entry
+type ImportResult struct {
+	// Source is the account name in the bundle
+	Source string
+	// Account is the imported account, nil when skipped
+	Account *config.Account
+	Outcome string
+	// HasADC reports whether saved credentials were restored
+	HasADC bool
+	// DroppedHooks reports that the account's hooks were not imported
+	DroppedHooks bool
+	// DroppedKubeContext is the linked kubectl context, not imported
+	// because it does not exist on this machine
+	DroppedKubeContext string
+}
+
+// ExportBundle writes the named accounts (all when names is empty) and their
+// saved ADC files to a bundle at path, encrypted with passphrase.
+// Machine-specific state (the ADC path) is dropped.
+func (m *Manager) ExportBundle(names []string, path, passphrase string) (*bundle.Bundle, error) {
+	if err := bundle.ValidatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		for _, account := range m.ListAccounts() {
+			names = append(names, account.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no accounts to export")
+	}
+
+	hostname, _ := os.Hostname()
+	b := &bundle.Bundle{
+		Version:   bundle.Version,
+		CreatedAt: time.Now(),
+		Hostname:  hostname,
+	}
+
+	for _, name := range names {
+		account, err := m.config.GetAccount(name)
+		if err != nil {
+			return nil, err
+		}
+
+		exported := *account
+		exported.ADCPath = ""
+		entry := bundle.Entry{Account: &exported}
+
+		if data, err := os.ReadFile(adc.GetStoragePath(name)); err == nil {
+			entry.ADC = data
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read saved ADC for '%s': %w", name, err)
+		}
+		b.Entries = append(b.Entries, entry)
+	}
+
+	err := bundle.Write(path, b, passphrase)
+	for _, entry := range b.Entries {
+		m.record(m.auditEvent(audit.ActionExport, entry.Account), err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ImportBundle adds the accounts of a bundle, storing their ADC and
+// recreating their gcloud configurations with the saved project and
+// properties. onConflict (see ConflictModes) decides what happens to
+// accounts whose name is already taken; renamed accounts get an
+// "-imported" suffix. Hooks are shell commands, so they are only imported
+// with withHooks. It stops at the first failure, returning the entries
+// handled so far.
+func (m *Manager) ImportBundle(ctx context.Context, b *bundle.Bundle, onConflict string, withHooks bool) ([]ImportResult, error) {
+	if !slices.Contains(ConflictModes, onConflict) {
+		return nil, fmt.Errorf("unknown conflict mode '%s', expected one of %v", onConflict, ConflictModes)
+	}
+
+	// Reject the whole bundle before changing anything
+	for _, entry := range b.Entries {
+		if entry.Account == nil {
+			return nil, fmt.Errorf("bundle contains an empty entry")
+		}
+		if err := validateImportName("account", entry.Account.Name); err != nil {
+			return nil, err
+		}
+		if configName := entry.Account.ConfigName; configName != "" {
+			if err := validateImportName("gcloud configuration", configName); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var results []ImportResult
+	for _, entry := range b.Entries {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		result, err := m.importEntry(ctx, entry, onConflict, withHooks)
+		if err != nil {
+			return results, fmt.Errorf("%s: %w", entry.Account.Name, err)
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// validateImportName rejects names from a bundle that could escape the
+// storage directory or be taken for a gcloud flag
+func validateImportName(kind, name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("bundle contains an %s without a name", kind)
+	case strings.ContainsAny(name, `/\`) || strings.Contains(name, ".."),
+		strings.HasPrefix(name, "-"), strings.HasPrefix(name, "."):
+		return fmt.Errorf("bundle contains an invalid %s name %q", kind, name)
+	}
+	return nil
+}
+
+func (m *Manager) importEntry(ctx context.Context, entry bundle.Entry, onConflict string, withHooks bool) (*ImportResult, error) {
+	source := entry.Account.Name
+	result := &ImportResult{Source: source, Outcome: ImportAdded}
+
+	account := *entry.Account
+	if !withHooks && len(account.Hooks) > 0 {
+		account.Hooks = nil
+		result.DroppedHooks = true
+	}
+	if account.KubeContext != "" {
+		if _, err := kube.FindContext(account.KubeContext); err != nil {
+			result.DroppedKubeContext = account.KubeContext
+			account.KubeContext = ""
+		}
+	}
+
+	existing, err := m.config.GetAccount(source)
+	if err == nil {
+		switch onConflict {
+		case ConflictSkip:
+			result.Outcome = ImportSkipped
+			return result, nil
+		case ConflictOverwrite:
+			if err := m.confirm(existing); err != nil {
+				return nil, err
+			}
+			result.Outcome = ImportOverwritten
+		case ConflictRename:
+			account.Name = m.importName(source)
+			account.ConfigName = fmt.Sprintf("%s-config", account.Name)
+			result.Outcome = ImportRenamed
+		}
+	}
+	if account.ConfigName == "" {
+		account.ConfigName = fmt.Sprintf("%s-config", account.Name)
+	}
+	account.ADCPath = ""
+	if account.CreatedAt.IsZero() {
+		account.CreatedAt = time.Now()
+	}
+
+	// Undo the gcloud configuration and ADC if a later step fails
+	createdConfig := !configExists(ctx, account.ConfigName)
+	storagePath := adc.GetStoragePath(account.Name)
+	previousADC, err := os.ReadFile(storagePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	undo := func() {
+		if createdConfig {
+			gcloud.DeleteConfig(context.WithoutCancel(ctx), account.ConfigName)
+		}
+		if previousADC != nil {
+			os.WriteFile(storagePath, previousADC, 0600)
+		} else {
+			os.Remove(storagePath)
+		}
+	}
+
+	if err := m.createImportedConfig(ctx, &account); err != nil {
+		undo()
+		return nil, err
+	}
+
+	if len(entry.ADC) > 0 {
+		adcPath, err := adc.WriteStorage(account.Name, entry.ADC)
+		if err != nil {
+			undo()
+			return nil, err
+		}
+		account.ADCPath = adcPath
+		result.HasADC = true
+	} else if result.Outcome == ImportOverwritten {
+		os.Remove(storagePath)
+	}
+
+	// Overwriting keeps links (registries, git URLs) pointing at the name
+	replaced := m.config.Accounts[account.Name]
+	m.config.Accounts[account.Name] = &account
+	err = m.config.Save()
+	m.record(m.auditEvent(audit.ActionImport, &account), err)
+	if err != nil {
+		if replaced != nil {
+			m.config.Accounts[account.Name] = replaced
+		} else {
+			delete(m.config.Accounts, account.Name)
+		}
+		undo()
+		return nil, err
+	}
+
+	if account.ADCPath != "" && account.QuotaProject != "" {
+		m.writeQuotaProject(&account)
+	}
+	if result.Outcome == ImportOverwritten && m.IsActive(account.Name) {
+		m.reporter.Warnf("'%s' is active; run 'gctx switch %s' to apply the imported credentials", account.Name, account.Name)
+	}
+
+	result.Account = &account
+	return result, nil
+}
+
+// createImportedConfig creates an imported account's gcloud configuration
+// (or updates an existing one) with its project and properties
+func (m *Manager) createImportedConfig(ctx context.Context, account *config.Account) error {
+	if err := gcloud.CreateConfig(ctx, account.ConfigName); err != nil {
+		return err
+	}
+	if account.ProjectID != "" {
+		if err := gcloud.SetConfigProperty(ctx, account.ConfigName, "core/project", account.ProjectID); err != nil {
+			return fmt.Errorf("failed to set project: %w", err)
+		}
+	}
+	for property, value := range account.Properties {
+		if err := gcloud.SetConfigProperty(ctx, account.ConfigName, property, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", property, err)
+		}
+	}
+	m.reporter.Infof("Created gcloud configuration: %s", account.ConfigName)
+	return nil
+}
+
+// importName returns a free name for an account imported under a taken one
+func (m *Manager) importName(name string) string {
+	candidate := name + "-imported"
+	for i := 2; ; i++ {
+		if _, err := m.config.GetAccount(candidate); err != nil {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s-imported-%d", name, i)
+	}
+}
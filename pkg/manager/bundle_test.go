@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/k0wl0n/gctx/pkg/bundle"
+	"github.com/k0wl0n/gctx/pkg/config"
+)
+
+func TestImportBundleRejectsUnsafeNames(t *testing.T) {
+	m := newTestManager(t)
+
+	for _, account := range []*config.Account{
+		{Name: ""},
+		{Name: "../x"},
+		{Name: "a/b"},
+		{Name: `a\b`},
+		{Name: ".."},
+		{Name: ".hidden"},
+		{Name: "--configuration=x"},
+		{Name: "ok", ConfigName: "../ok-config"},
+		{Name: "ok", ConfigName: "--quiet"},
+	} {
+		b := &bundle.Bundle{Entries: []bundle.Entry{
+			{Account: &config.Account{Name: "fine"}},
+			{Account: account},
+		}}
+		results, err := m.ImportBundle(context.Background(), b, ConflictSkip, false)
+		if err == nil {
+			t.Errorf("ImportBundle(%q, %q) succeeded", account.Name, account.ConfigName)
+		}
+		if len(results) != 0 {
+			t.Errorf("ImportBundle(%q, %q) imported %d entries before rejecting the bundle",
+				account.Name, account.ConfigName, len(results))
+		}
+	}
+
+	if _, err := m.config.GetAccount("fine"); err == nil {
+		t.Error("an entry of a rejected bundle was imported")
+	}
+}
+
+func TestImportBundleRejectsUnknownConflictMode(t *testing.T) {
+	m := newTestManager(t)
+	b := &bundle.Bundle{Entries: []bundle.Entry{{Account: &config.Account{Name: "fine"}}}}
+
+	if _, err := m.ImportBundle(context.Background(), b, "merge", false); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,72 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/k0wl0n/gctx/pkg/adc"
+	"github.com/k0wl0n/gctx/pkg/gcloud"
+)
+
+// CaptureResult describes credentials picked up by CaptureADC
+type CaptureResult struct {
+	Account string
+	Path    string
+	Email   string
+}
+
+// CaptureADC saves the default ADC into the active account's storage when it
+// holds credentials gctx has not stored yet, e.g. after a manual
+// `gcloud auth application-default login`. It returns nil when there is
+// nothing to save. Credentials are only attributed to the active account
+// while gcloud's active configuration is the account's own, so that ADC
+// restored by a switch in progress is never saved under the wrong account.
+func (m *Manager) CaptureADC(ctx context.Context) (*CaptureResult, error) {
+	name := m.config.ActiveAccount
+	if name == "" {
+		m.reporter.Warnf("no active account; ADC change not saved")
+		return nil, nil
+	}
+	account, err := m.config.GetAccount(name)
+	if err != nil {
+		return nil, err
+	}
+
+	activeConfig, err := gcloud.ReadActiveConfig()
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if activeConfig != account.ConfigName {
+		m.reporter.Warnf("gcloud configuration '%s' does not belong to active account '%s'; ADC change not saved",
+			activeConfig, name)
+		return nil, nil
+	}
+
+	defaultPath := adc.GetDefaultADCPath()
+	data, err := os.ReadFile(defaultPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := adc.ValidateADC(defaultPath); err != nil {
+		return nil, fmt.Errorf("invalid ADC file: %w", err)
+	}
+
+	// Credentials restored by a switch, or already saved
+	for _, other := range m.config.ListAccounts() {
+		stored, err := os.ReadFile(adc.GetStoragePath(other.Name))
+		if err == nil && bytes.Equal(stored, data) {
+			return nil, nil
+		}
+	}
+
+	path, err := m.SaveCredentials(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &CaptureResult{Account: name, Path: path, Email: account.Email}, nil
+}
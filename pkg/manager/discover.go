@@ -0,0 +1,154 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/adc"
+	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/oauth"
+	"github.com/k0wl0n/gctx/pkg/resourcemanager"
+	"github.com/ktr0731/go-fuzzyfinder"
+)
+
+const defaultProjectsCacheTTL = time.Hour
+
+// Environment variables overriding the configured API endpoints
+const (
+	TokenEndpointEnv           = "GCTX_TOKEN_ENDPOINT"
+	ResourceManagerEndpointEnv = "GCTX_RESOURCE_MANAGER_ENDPOINT"
+)
+
+type projectsCache struct {
+	FetchedAt time.Time                 `json:"fetched_at"`
+	Projects  []resourcemanager.Project `json:"projects"`
+}
+
+func endpoint(env, configured string) string {
+	if v := os.Getenv(env); v != "" {
+		return v
+	}
+	return configured
+}
+
+// AccessToken mints an access token from an account's saved credentials
+func (m *Manager) AccessToken(ctx context.Context, name string) (*oauth.Token, error) {
+	if _, err := m.config.GetAccount(name); err != nil {
+		return nil, err
+	}
+
+	cred, err := adc.LoadADC(adc.GetStoragePath(name))
+	if err != nil {
+		return nil, fmt.Errorf("no usable saved ADC for '%s': %w", name, err)
+	}
+	if cred.Type != "authorized_user" {
+		return nil, fmt.Errorf("unsupported credential type for '%s': %s", name, cred.Type)
+	}
+
+	return oauth.RefreshToken(ctx,
+		endpoint(TokenEndpointEnv, m.config.Endpoints.Token),
+		cred.ClientID, cred.ClientSecret, cred.RefreshToken)
+}
+
+// DiscoverProjects lists the projects an account's saved credentials can
+// access. Results are cached per account; refresh bypasses the cache.
+func (m *Manager) DiscoverProjects(ctx context.Context, name string, refresh bool) ([]resourcemanager.Project, error) {
+	cachePath, err := projectsCachePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(m.config.ProjectsCacheTTL)
+	if ttl <= 0 {
+		ttl = defaultProjectsCacheTTL
+	}
+
+	if !refresh {
+		if cached, err := loadProjectsCache(cachePath); err == nil &&
+			time.Since(cached.FetchedAt) < ttl {
+			return cached.Projects, nil
+		}
+	}
+
+	token, err := m.AccessToken(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := resourcemanager.ListProjects(ctx,
+		endpoint(ResourceManagerEndpointEnv, m.config.Endpoints.ResourceManager),
+		token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := saveProjectsCache(cachePath, &projectsCache{
+		FetchedAt: time.Now(),
+		Projects:  projects,
+	}); err != nil {
+		m.reporter.Warnf("failed to cache projects: %v", err)
+	}
+
+	return projects, nil
+}
+
+// SelectDiscoveredProject launches an interactive UI to select one of the
+// projects an account can access
+func (m *Manager) SelectDiscoveredProject(ctx context.Context, name string) (string, error) {
+	projects, err := m.DiscoverProjects(ctx, name, false)
+	if err != nil {
+		return "", err
+	}
+	if len(projects) == 0 {
+		return "", fmt.Errorf("account '%s' cannot access any project", name)
+	}
+
+	idx, err := fuzzyfinder.Find(
+		projects,
+		func(i int) string {
+			return fmt.Sprintf("%s (%s)", projects[i].ProjectID, projects[i].Name)
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return projects[idx].ProjectID, nil
+}
+
+func projectsCachePath(name string) (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache", "projects", name+".json"), nil
+}
+
+func loadProjectsCache(path string) (*projectsCache, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cached projectsCache
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, err
+	}
+	return &cached, nil
+}
+
+func saveProjectsCache(path string, cached *projectsCache) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cached, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,131 @@
+package manager
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/adc"
+	"github.com/k0wl0n/gctx/pkg/config"
+)
+
+// newTestManager returns a manager for a single account "work" with saved
+// ADC, in a temporary home directory
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	m, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.config.Accounts["work"] = &config.Account{Name: "work", ConfigName: "work-config", ProjectID: "p"}
+
+	path := adc.GetStoragePath("work")
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	cred := `{"type":"authorized_user","client_id":"id","client_secret":"secret","refresh_token":"refresh"}`
+	if err := os.WriteFile(path, []byte(cred), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// stubAPIs points the token and Resource Manager endpoints at a local
+// server and returns the number of project listings it served
+func stubAPIs(t *testing.T) *atomic.Int32 {
+	t.Helper()
+	var listings atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			w.Write([]byte(`{"access_token":"tok","expires_in":3600}`))
+		case "/v1/projects":
+			listings.Add(1)
+			w.Write([]byte(`{"projects":[{"projectId":"p","name":"P"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Setenv(TokenEndpointEnv, srv.URL+"/token")
+	t.Setenv(ResourceManagerEndpointEnv, srv.URL)
+	return &listings
+}
+
+func TestDiscoverProjectsCache(t *testing.T) {
+	m := newTestManager(t)
+	listings := stubAPIs(t)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		projects, err := m.DiscoverProjects(ctx, "work", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(projects) != 1 || projects[0].ProjectID != "p" {
+			t.Fatalf("projects = %+v", projects)
+		}
+	}
+	if n := listings.Load(); n != 1 {
+		t.Errorf("listings = %d, want 1 (second call served from cache)", n)
+	}
+
+	if _, err := m.DiscoverProjects(ctx, "work", true); err != nil {
+		t.Fatal(err)
+	}
+	if n := listings.Load(); n != 2 {
+		t.Errorf("listings = %d, want 2 after refresh", n)
+	}
+}
+
+func TestDiscoverProjectsCacheExpiry(t *testing.T) {
+	m := newTestManager(t)
+	listings := stubAPIs(t)
+	ctx := context.Background()
+	m.config.ProjectsCacheTTL = config.Duration(time.Minute)
+
+	if _, err := m.DiscoverProjects(ctx, "work", false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Age the cache beyond the TTL
+	path, err := projectsCachePath("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := loadProjectsCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached.FetchedAt = time.Now().Add(-2 * time.Minute)
+	if err := saveProjectsCache(path, cached); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.DiscoverProjects(ctx, "work", false); err != nil {
+		t.Fatal(err)
+	}
+	if n := listings.Load(); n != 2 {
+		t.Errorf("listings = %d, want 2 after the cache expired", n)
+	}
+}
+
+func TestDiscoverProjectsUnknownAccount(t *testing.T) {
+	m := newTestManager(t)
+	stubAPIs(t)
+
+	if _, err := m.DiscoverProjects(context.Background(), "missing", false); err == nil {
+		t.Fatal("expected an error")
+	}
+}
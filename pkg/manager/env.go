@@ -0,0 +1,91 @@
+package manager
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/k0wl0n/gctx/pkg/adc"
+)
+
+// Environment profiles accepted by ProfileEnv
+const (
+	ProfileTerraform  = "terraform"
+	ProfileGcloud     = "gcloud"
+	ProfileClientLibs = "client-libs"
+	// ProfileDotenv combines all other profiles, for .env files read by
+	// several tools
+	ProfileDotenv = "dotenv"
+)
+
+// Profiles lists the environment profiles
+var Profiles = []string{ProfileTerraform, ProfileGcloud, ProfileClientLibs, ProfileDotenv}
+
+// ProfileEnv returns the environment variables through which a consumer
+// (see Profiles) picks up an account: its saved credentials, project
+// (project overrides it when non-empty), quota project and the region, zone
+// and service account impersonation set as account properties. Variables
+// without a value are omitted.
+func (m *Manager) ProfileEnv(name, profile, project string) (map[string]string, error) {
+	account, err := m.config.GetAccount(name)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(Profiles, profile) {
+		return nil, fmt.Errorf("unknown profile '%s', expected one of %v", profile, Profiles)
+	}
+
+	if project == "" {
+		project = account.ProjectID
+	}
+	credentials := ""
+	if storagePath := adc.GetStoragePath(account.Name); fileExists(storagePath) {
+		credentials = storagePath
+	}
+	region := account.Properties["compute/region"]
+	zone := account.Properties["compute/zone"]
+	impersonate := account.Properties["auth/impersonate_service_account"]
+
+	env := map[string]string{"GCTX_ACCOUNT": account.Name}
+	add := func(vars map[string]string) {
+		for key, value := range vars {
+			if value != "" {
+				env[key] = value
+			}
+		}
+	}
+
+	if profile == ProfileTerraform || profile == ProfileDotenv {
+		add(map[string]string{
+			"GOOGLE_APPLICATION_CREDENTIALS":     credentials,
+			"GOOGLE_PROJECT":                     project,
+			"GOOGLE_REGION":                      region,
+			"GOOGLE_ZONE":                        zone,
+			"GOOGLE_IMPERSONATE_SERVICE_ACCOUNT": impersonate,
+		})
+		if account.QuotaProject != "" {
+			add(map[string]string{
+				"GOOGLE_BILLING_PROJECT": account.QuotaProject,
+				"USER_PROJECT_OVERRIDE":  "true",
+			})
+		}
+	}
+	if profile == ProfileGcloud || profile == ProfileDotenv {
+		add(map[string]string{
+			"CLOUDSDK_ACTIVE_CONFIG_NAME":               account.ConfigName,
+			"CLOUDSDK_CORE_PROJECT":                     project,
+			"CLOUDSDK_COMPUTE_REGION":                   region,
+			"CLOUDSDK_COMPUTE_ZONE":                     zone,
+			"CLOUDSDK_AUTH_IMPERSONATE_SERVICE_ACCOUNT": impersonate,
+			"CLOUDSDK_BILLING_QUOTA_PROJECT":            account.QuotaProject,
+		})
+	}
+	if profile == ProfileClientLibs || profile == ProfileDotenv {
+		add(map[string]string{
+			"GOOGLE_APPLICATION_CREDENTIALS": credentials,
+			"GOOGLE_CLOUD_PROJECT":           project,
+			"GOOGLE_CLOUD_QUOTA_PROJECT":     account.EffectiveQuotaProject(),
+		})
+	}
+
+	return env, nil
+}
@@ -0,0 +1,110 @@
+package manager
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+)
+
+// GitCredential is what git receives for a repository
+type GitCredential struct {
+	Username string
+	Password string
+	Expiry   time.Time
+}
+
+// IsGoogleGitHost reports whether host serves Google-hosted git: Cloud
+// Source Repositories or a Secure Source Manager instance
+func IsGoogleGitHost(host string) bool {
+	return host == "source.developers.google.com" ||
+		strings.HasSuffix(host, ".sourcemanager.dev")
+}
+
+// normalizeGitURL drops trailing slashes so that links match consistently
+func normalizeGitURL(rawURL string) string {
+	return strings.TrimRight(strings.TrimSpace(rawURL), "/")
+}
+
+// LinkGitURLs makes an account provide credentials for repositories under
+// the given URL prefixes (e.g. https://source.developers.google.com/p/proj)
+func (m *Manager) LinkGitURLs(name string, urls []string) error {
+	if _, err := m.config.GetAccount(name); err != nil {
+		return err
+	}
+
+	if m.config.GitURLs == nil {
+		m.config.GitURLs = make(map[string]string)
+	}
+	for _, u := range urls {
+		m.config.GitURLs[normalizeGitURL(u)] = name
+	}
+	return m.config.Save()
+}
+
+// UnlinkGitURLs removes the account links of URL prefixes
+func (m *Manager) UnlinkGitURLs(urls []string) error {
+	for _, u := range urls {
+		delete(m.config.GitURLs, normalizeGitURL(u))
+	}
+	return m.config.Save()
+}
+
+// GitURLs returns the linked URL prefixes and their accounts
+func (m *Manager) GitURLs() map[string]string {
+	return m.config.GitURLs
+}
+
+// GitAccount resolves the account for a repository URL: the account linked
+// to the longest matching URL prefix or, for Google-hosted git, the account
+// of the directory git runs in. It returns nil if neither applies.
+func (m *Manager) GitAccount(rawURL, dir string) (*config.Account, error) {
+	rawURL = normalizeGitURL(rawURL)
+
+	best := ""
+	for prefix := range m.config.GitURLs {
+		if (rawURL == prefix || strings.HasPrefix(rawURL, prefix+"/")) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best != "" {
+		return m.config.GetAccount(m.config.GitURLs[best])
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || !IsGoogleGitHost(u.Hostname()) {
+		return nil, nil
+	}
+
+	dirCtx, err := m.ResolveDirectory(dir)
+	if err != nil || dirCtx == nil {
+		return nil, err
+	}
+	return dirCtx.Account, nil
+}
+
+// GitCredentials returns credentials for a repository minted from the
+// saved ADC of its account (see GitAccount), or nil if no account applies
+func (m *Manager) GitCredentials(ctx context.Context, rawURL, dir string) (*GitCredential, error) {
+	account, err := m.GitAccount(rawURL, dir)
+	if err != nil || account == nil {
+		return nil, err
+	}
+
+	token, err := m.AccessToken(ctx, account.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	username := account.Email
+	if username == "" {
+		username = RegistryUsername
+	}
+	return &GitCredential{
+		Username: username,
+		Password: token.AccessToken,
+		Expiry:   token.Expiry,
+	}, nil
+}
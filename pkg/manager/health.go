@@ -0,0 +1,88 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/adc"
+	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/oauth"
+)
+
+// maxConcurrentChecks bounds the number of token refreshes in flight
+const maxConcurrentChecks = 8
+
+// CheckResult is the outcome of verifying an account's saved credentials
+type CheckResult struct {
+	Account string
+	Status  string
+	// Err explains a non-OK status
+	Err error
+}
+
+func setCredentialStatus(account *config.Account, status string) {
+	account.CredentialStatus = status
+	account.CredentialCheckedAt = time.Now()
+}
+
+// checkCredentials refreshes an access token from the saved credentials
+func (m *Manager) checkCredentials(ctx context.Context, name string) CheckResult {
+	result := CheckResult{Account: name, Status: config.CredentialOK}
+
+	if _, err := m.AccessToken(ctx, name); err != nil {
+		result.Err = err
+		result.Status = config.CredentialError
+		if errors.Is(err, oauth.ErrReauthRequired) || !fileExists(adc.GetStoragePath(name)) {
+			result.Status = config.CredentialNeedsLogin
+		}
+	}
+
+	return result
+}
+
+// CheckCredentials verifies the saved credentials of the named accounts
+// concurrently, by refreshing an access token against the OAuth endpoint,
+// and records the outcome on each account. Results are in the order of names.
+// If ctx is cancelled, the outcomes determined so far are recorded and the
+// context's error is returned.
+func (m *Manager) CheckCredentials(ctx context.Context, names []string) ([]CheckResult, error) {
+	for _, name := range names {
+		if _, err := m.config.GetAccount(name); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]CheckResult, len(names))
+	sem := make(chan struct{}, maxConcurrentChecks)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = m.checkCredentials(ctx, name)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		// A check cut short by cancellation says nothing about the
+		// credentials, so the last known status is kept
+		if result.Err != nil && ctx.Err() != nil && errors.Is(result.Err, ctx.Err()) {
+			continue
+		}
+		account, _ := m.config.GetAccount(result.Account)
+		setCredentialStatus(account, result.Status)
+	}
+
+	if err := m.config.Save(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/hooks"
+)
+
+// Hook events, used as keys of config.Hooks
+const (
+	HookPreSwitch  = "pre-switch"
+	HookPostSwitch = "post-switch"
+	HookPreCreate  = "pre-create"
+	HookPostCreate = "post-create"
+	HookPreLogin   = "pre-login"
+	HookPostLogin  = "post-login"
+	HookPreDelete  = "pre-delete"
+	HookPostDelete = "post-delete"
+)
+
+// hookInput is the JSON document hooks receive on stdin
+type hookInput struct {
+	Event           string          `json:"event"`
+	Account         *config.Account `json:"account"`
+	PreviousAccount string          `json:"previous_account,omitempty"`
+}
+
+// runHooks runs the global hooks for event, then the account's own.
+// previous is the account that was active before the operation. A
+// failing pre-hook aborts the operation: its error is returned and the
+// remaining hooks are skipped. Failing post-hooks are only reported, since
+// the operation has already happened.
+func (m *Manager) runHooks(ctx context.Context, event string, account *config.Account, previous string) error {
+	commands := slices.Concat(m.config.Hooks[event], account.Hooks[event])
+	if len(commands) == 0 {
+		return nil
+	}
+
+	input, err := json.Marshal(hookInput{
+		Event:           event,
+		Account:         account,
+		PreviousAccount: previous,
+	})
+	if err != nil {
+		return err
+	}
+
+	env := accountEnv(account, "")
+	env["GCTX_HOOK"] = event
+	env["GCTX_PROJECT"] = account.ProjectID
+	env["GCTX_EMAIL"] = account.Email
+	env["GCTX_PREVIOUS_ACCOUNT"] = previous
+
+	timeout := time.Duration(m.config.HookTimeout)
+	for _, command := range commands {
+		if err := hooks.Run(ctx, command, env, input, m.hookOutput, timeout); err != nil {
+			err = fmt.Errorf("%s hook '%s' failed: %w", event, command, err)
+			if !isPostHook(event) {
+				return err
+			}
+			m.reporter.Warnf("%v", err)
+		}
+	}
+	return nil
+}
+
+func isPostHook(event string) bool {
+	switch event {
+	case HookPostSwitch, HookPostCreate, HookPostLogin, HookPostDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package manager
+
+import (
+	"fmt"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/kube"
+)
+
+// kubeUserName names the user entry generated for an account
+func kubeUserName(account *config.Account) string {
+	return "gctx-" + account.Name
+}
+
+// LinkKubeContext links a kubectl context to an account, so that switching
+// to the account makes it the current context. With pinAuth, the context
+// authenticates through gke-gcloud-auth-plugin with the account's gcloud
+// configuration, whichever account is active.
+func (m *Manager) LinkKubeContext(name, contextName string, pinAuth bool) (*config.Account, error) {
+	account, err := m.config.GetAccount(name)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := kube.FindContext(contextName)
+	if err != nil {
+		return nil, err
+	}
+
+	if pinAuth {
+		user := kubeUserName(account)
+		if err := file.SetAuthPluginUser(user, account.ConfigName); err != nil {
+			return nil, err
+		}
+		if err := file.SetContextUser(contextName, user); err != nil {
+			return nil, err
+		}
+		if err := file.Save(); err != nil {
+			return nil, fmt.Errorf("failed to update kubeconfig: %w", err)
+		}
+		m.reporter.Infof("Context '%s' now authenticates as %s (%s)", contextName, user, file.Path)
+	}
+
+	account.KubeContext = contextName
+	if err := m.config.Save(); err != nil {
+		return nil, err
+	}
+
+	if m.IsActive(name) {
+		m.useKubeContext(account)
+	}
+	return account, nil
+}
+
+// UnlinkKubeContext removes the kubectl context linked to an account
+func (m *Manager) UnlinkKubeContext(name string) error {
+	account, err := m.config.GetAccount(name)
+	if err != nil {
+		return err
+	}
+
+	account.KubeContext = ""
+	return m.config.Save()
+}
+
+// useKubeContext makes the account's linked context current, if any
+func (m *Manager) useKubeContext(account *config.Account) {
+	if account.KubeContext == "" {
+		return
+	}
+	if err := kube.UseContext(account.KubeContext); err != nil {
+		m.reporter.Warnf("failed to switch kubectl context: %v", err)
+	}
+}
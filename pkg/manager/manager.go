@@ -1,38 +1,100 @@
 package manager
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/k0wl0n/gctx/pkg/adc"
+	"github.com/k0wl0n/gctx/pkg/audit"
 	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/dirconfig"
 	"github.com/k0wl0n/gctx/pkg/gcloud"
+	"github.com/k0wl0n/gctx/pkg/history"
+	"github.com/k0wl0n/gctx/pkg/selector"
 	"github.com/k0wl0n/gctx/pkg/watcher"
 	"github.com/ktr0731/go-fuzzyfinder"
 )
 
+// adcSettleTimeout bounds the wait for the ADC file once gcloud has exited
+const adcSettleTimeout = 2 * time.Second
+
 type Manager struct {
-	config *config.Config
+	config   *config.Config
+	reporter Reporter
+	// command names the CLI command driving the manager (e.g. "switch")
+	command   string
+	confirmer Confirmer
+	// hookOutput receives the standard output of hooks
+	hookOutput io.Writer
+}
+
+// CreateResult describes the outcome of CreateAccount
+type CreateResult struct {
+	Account *config.Account
+	// Login is nil when the account was created without auto-save
+	Login *LoginResult
+}
+
+// LoginResult describes the outcome of an authentication flow
+type LoginResult struct {
+	Account  *config.Account
+	ADCPath  string
+	Warnings []string
+}
+
+// AccountInfo holds the details shown for a single account
+type AccountInfo struct {
+	Account *config.Account
+	// ADCModTime is zero when no saved ADC file is present
+	ADCModTime time.Time
+	// ADCQuotaProject is the quota_project_id of the saved ADC file
+	ADCQuotaProject string
+	Active          bool
+	Warnings        []string
 }
 
-func New() (*Manager, error) {
+func New(opts ...Option) (*Manager, error) {
 	cfg, err := config.Load()
 	if err != nil {
 		return nil, err
 	}
-	return &Manager{config: cfg}, nil
+	m := &Manager{config: cfg, reporter: nopReporter{}, hookOutput: os.Stderr}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
 }
 
 // SelectAccountInteractive launches an interactive UI to select an account
-func (m *Manager) SelectAccountInteractive() (string, error) {
-	accounts := m.config.ListAccounts()
+// among those matching sel
+func (m *Manager) SelectAccountInteractive(sel selector.Selector) (string, error) {
+	accounts := m.SelectAccounts(sel)
 	if len(accounts) == 0 {
+		if len(sel) > 0 {
+			return "", fmt.Errorf("no accounts match selector '%s'", sel)
+		}
 		return "", fmt.Errorf("no accounts configured")
 	}
 
+	// Order by recency so recently used accounts sit next to the prompt
+	var lastUsed map[string]time.Time
+	if entries, err := history.Load(); err == nil {
+		lastUsed = history.LastUsed(entries)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		ti, tj := lastUsed[accounts[i].Name], lastUsed[accounts[j].Name]
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return accounts[i].Name < accounts[j].Name
+	})
+
 	idx, err := fuzzyfinder.Find(
 		accounts,
 		func(i int) string {
@@ -48,10 +110,11 @@ func (m *Manager) SelectAccountInteractive() (string, error) {
 				return ""
 			}
 			acc := accounts[i]
-			return fmt.Sprintf("Name: %s\nProject: %s\nEmail: %s\nCreated: %s",
+			return fmt.Sprintf("Name: %s\nProject: %s\nEmail: %s\nTags: %s\nCreated: %s",
 				acc.Name,
 				acc.ProjectID,
 				acc.Email,
+				selector.FormatTags(acc.Tags),
 				acc.CreatedAt.Format("2006-01-02 15:04:05"),
 			)
 		}),
@@ -64,25 +127,82 @@ func (m *Manager) SelectAccountInteractive() (string, error) {
 	return accounts[idx].Name, nil
 }
 
-// CreateAccount creates a new account with optional auto-save
-func (m *Manager) CreateAccount(name, projectID string, autoSave bool) error {
+// CreateAccount creates a new account with optional auto-save. With
+// auto-save and an empty projectID, the project is picked interactively
+// among those the account can access once it has credentials. props are
+// extra gcloud properties, applied before authentication so that e.g. proxy
+// settings take effect.
+//
+// If any step fails or is interrupted, the previously active account,
+// gcloud configuration and ADC are restored and the new account is removed.
+func (m *Manager) CreateAccount(ctx context.Context, name, projectID string, props map[string]string, autoSave bool) (*CreateResult, error) {
+	for property := range props {
+		if err := ValidateProperty(property); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := m.config.GetAccount(name); err == nil {
+		return nil, fmt.Errorf("account '%s' already exists", name)
+	}
+
+	pending := &config.Account{
+		Name:       name,
+		ConfigName: fmt.Sprintf("%s-config", name),
+		ProjectID:  projectID,
+		Properties: props,
+	}
+	previous := m.config.ActiveAccount
+	if err := m.runHooks(ctx, HookPreCreate, pending, previous); err != nil {
+		return nil, err
+	}
+
+	tx, err := m.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := m.createAccount(ctx, tx, name, projectID, props, autoSave)
+	err = tx.end(err)
+
+	account := pending
+	if err == nil {
+		account = result.Account
+	}
+	m.record(m.auditEvent(audit.ActionCreate, account), err)
+
+	if err != nil {
+		return nil, err
+	}
+	m.runHooks(ctx, HookPostCreate, account, previous)
+	return result, nil
+}
+
+func (m *Manager) createAccount(ctx context.Context, tx *transaction, name, projectID string, props map[string]string, autoSave bool) (*CreateResult, error) {
 	configName := fmt.Sprintf("%s-config", name)
 
 	// Create gcloud config
-	if err := gcloud.CreateConfig(configName); err != nil {
-		return err
+	if !configExists(ctx, configName) {
+		tx.createdConfig = configName
+	} else if err := tx.snapshotConfig(configName); err != nil {
+		return nil, err
 	}
-	fmt.Printf("Created gcloud configuration: %s\n", configName)
+	if err := gcloud.CreateConfig(ctx, configName); err != nil {
+		return nil, err
+	}
+	m.reporter.Infof("Created gcloud configuration: %s", configName)
 
 	// Activate and set project
-	if err := gcloud.ActivateConfig(configName); err != nil {
-		return err
+	if err := gcloud.ActivateConfig(ctx, configName); err != nil {
+		return nil, err
 	}
 
-	if err := gcloud.SetProject(projectID); err != nil {
-		return err
+	// The project may be picked after authentication, see SetProject
+	if projectID != "" {
+		if err := gcloud.SetProject(ctx, projectID); err != nil {
+			return nil, err
+		}
+		m.reporter.Infof("Set project: %s", projectID)
 	}
-	fmt.Printf("Set project: %s\n", projectID)
 
 	// Add to config
 	account := &config.Account{
@@ -92,273 +212,503 @@ func (m *Manager) CreateAccount(name, projectID string, autoSave bool) error {
 		CreatedAt:  time.Now(),
 	}
 
+	for property, value := range props {
+		if value == "" {
+			continue
+		}
+		if err := gcloud.SetConfigProperty(ctx, configName, property, value); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", property, err)
+		}
+		if account.Properties == nil {
+			account.Properties = make(map[string]string)
+		}
+		account.Properties[property] = value
+		m.reporter.Infof("Set %s: %s", property, value)
+	}
+
 	if err := m.config.AddAccount(account); err != nil {
-		return err
+		return nil, err
 	}
-	fmt.Printf("Account '%s' added to configuration.\n\n", name)
+	tx.createdAccount = name
+	m.reporter.Infof("Account '%s' added to configuration.", name)
 
+	result := &CreateResult{Account: account}
 	if autoSave {
-		return m.autoSaveFlow(name)
-	}
+		login, err := m.autoSaveFlow(ctx, tx, name)
+		if err != nil {
+			return nil, err
+		}
+		result.Login = login
 
-	// Manual flow
-	fmt.Println("Now run the following commands:")
-	fmt.Println("  1. gcloud auth login")
-	fmt.Println("  2. gcloud auth application-default login")
-	fmt.Printf("  3. gctx save %s\n", name)
+		if projectID == "" {
+			selected, err := m.SelectDiscoveredProject(ctx, name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to select project: %w", err)
+			}
+			if _, err := m.SetProject(ctx, name, selected); err != nil {
+				return nil, err
+			}
+			m.reporter.Infof("Set project: %s", selected)
+		}
+	}
 
-	return nil
+	return result, tx.check()
 }
 
-// Login runs authentication flow for an existing account
-func (m *Manager) Login(name string) error {
+// Login runs authentication flow for an existing account. If it fails or is
+// interrupted, the previously active account, gcloud configuration and ADC
+// are restored.
+func (m *Manager) Login(ctx context.Context, name string) (*LoginResult, error) {
 	// Check if account exists
-	_, err := m.config.GetAccount(name)
+	account, err := m.config.GetAccount(name)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	if err := m.confirm(account); err != nil {
+		return nil, err
+	}
+	previous := m.config.ActiveAccount
+	if err := m.runHooks(ctx, HookPreLogin, account, previous); err != nil {
+		return nil, err
+	}
+
+	tx, err := m.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := m.login(ctx, tx, account)
+	err = tx.end(err)
+	m.record(m.auditEvent(audit.ActionLogin, account), err)
 
-	// Switch to the account first to ensure we are updating the right gcloud config
-	if err := m.SwitchAccount(name); err != nil {
-		return fmt.Errorf("failed to switch to account before login: %w", err)
+	if err != nil {
+		return nil, err
 	}
 
-	return m.autoSaveFlow(name)
+	// The account stays active: complete the switch now that nothing can
+	// be rolled back anymore
+	if previous != name {
+		m.useKubeContext(account)
+		m.recordSwitch(previous, name)
+	}
+	m.runHooks(ctx, HookPostLogin, account, previous)
+	return result, nil
 }
 
-func (m *Manager) autoSaveFlow(accountName string) error {
-	fmt.Println("Running authentication...")
+func (m *Manager) login(ctx context.Context, tx *transaction, account *config.Account) (*LoginResult, error) {
+	// Activate the account first to ensure we are updating the right gcloud
+	// config. An account that never saved ADC has none to restore: the
+	// login is what provides it.
+	activate := m.activate
+	if !fileExists(adc.GetStoragePath(account.Name)) {
+		activate = m.activateConfig
+	}
+	if err := activate(ctx, account); err != nil {
+		return nil, fmt.Errorf("failed to activate account before login: %w", err)
+	}
+
+	return m.autoSaveFlow(ctx, tx, account.Name)
+}
+
+func (m *Manager) autoSaveFlow(ctx context.Context, tx *transaction, accountName string) (*LoginResult, error) {
+	m.reporter.Infof("Running authentication...")
 
 	// Run gcloud auth login
-	if err := gcloud.AuthLogin(); err != nil {
-		return fmt.Errorf("auth login failed: %w", err)
-	}
-	fmt.Println("Logged in successfully.")
-
-	// Run gcloud auth application-default login
-	fmt.Println("Running ADC authentication...")
-	// Start watching before triggering auth to ensure we catch the file creation/update
-	// However, auth is interactive, so we can't block here.
-	// The original design had WatchADC *after* starting auth command but `AuthADCLogin` blocks.
-	// But `AuthADCLogin` is interactive (opens browser).
-	// Let's rely on `watcher.WatchADC` being called *after* `AuthADCLogin` returns?
-	// Wait, `AuthADCLogin` waits for the command to finish. When it finishes, the file should be there.
-	// So strictly speaking, we might not need a watcher if `gcloud` guarantees the file is written before it exits.
-	// But let's follow the architecture: maybe the file takes a moment to appear or we want to verify it.
-	// Actually, `AuthADCLogin` blocks until the user completes the flow in the browser.
-	// So when it returns, the file *should* be there.
-	// The `watcher` might be useful if we run `AuthADCLogin` in background or if we want to be extra sure.
-	// In the provided architecture `watcher.WatchADC` is called *after* `AuthADCLogin`.
-	// This implies we are just verifying the file was created/updated.
-
-	warnings, err := gcloud.AuthADCLogin()
+	if err := gcloud.AuthLogin(ctx); err != nil {
+		return nil, fmt.Errorf("auth login failed: %w", err)
+	}
+	if err := tx.check(); err != nil {
+		return nil, err
+	}
+	m.reporter.Infof("Logged in successfully.")
+
+	// Run gcloud auth application-default login. Watching starts first so
+	// that the write is caught however quickly gcloud exits afterwards.
+	m.reporter.Infof("Running ADC authentication...")
+	watchCtx, stopWatching := context.WithCancel(ctx)
+	defer stopWatching()
+	events, err := watcher.Watch(watchCtx, adc.GetDefaultADCPath())
 	if err != nil {
-		return fmt.Errorf("ADC auth failed: %w", err)
+		m.reporter.Warnf("Watcher warning: %v", err)
 	}
 
-	// Watch for ADC file (verification)
-	// Since the command finished, we just check if it's there and valid.
-	// But let's use the watcher as requested, maybe with a short timeout since it should be immediate.
-	if err := watcher.WatchADC(5 * time.Second); err != nil {
+	warnings, err := gcloud.AuthADCLogin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ADC auth failed: %w", err)
+	}
+	if err := tx.check(); err != nil {
+		return nil, err
+	}
+
+	// gcloud has exited, so the new file only has to settle
+	m.reporter.Infof("Watching for ADC file changes...")
+	waitCtx, cancel := context.WithTimeout(watchCtx, adcSettleTimeout)
+	defer cancel()
+	if _, err := watcher.WatchADC(waitCtx, events); err != nil {
 		// If watcher fails, it might mean the file wasn't updated or created.
 		// But let's try to proceed anyway if the file exists.
-		fmt.Printf("Watcher warning: %v\n", err)
+		m.reporter.Warnf("Watcher warning: %v", err)
 	}
 
 	// Auto-save
 	adcPath, err := adc.SaveADC(accountName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Update config with ADC path and email
 	account, _ := m.config.GetAccount(accountName)
 	account.ADCPath = adcPath
-	account.Email, _ = adc.GetADCEmail(adc.GetDefaultADCPath())
+	setCredentialStatus(account, config.CredentialOK)
+	account.Email, _ = adc.GetADCEmail(ctx, adc.GetDefaultADCPath())
 	m.config.Save()
 
-	fmt.Printf("ADC credentials auto-saved for: %s\n", accountName)
-	fmt.Printf("Saved to: %s\n\n", adcPath)
-
-	// Show warnings
-	if len(warnings) > 0 {
-		fmt.Println("Warnings:")
-		for _, w := range warnings {
-			fmt.Printf("   %s\n", w)
-		}
-		fmt.Println()
+	// gcloud fills quota_project_id from core/project; restore our choice
+	if account.QuotaProject != "" {
+		m.writeQuotaProject(account)
 	}
 
-	fmt.Printf("Account '%s' is ready to use!\n", accountName)
-	fmt.Printf("Run: gctx switch %s\n", accountName)
+	return &LoginResult{
+		Account:  account,
+		ADCPath:  adcPath,
+		Warnings: warnings,
+	}, nil
+}
 
-	return nil
+// SwitchAccount switches to a different account. Protected accounts must be
+// approved by the configured Confirmer.
+func (m *Manager) SwitchAccount(ctx context.Context, name string) (*config.Account, error) {
+	return m.switchAccount(ctx, name, false)
 }
 
-// SwitchAccount switches to a different account
-func (m *Manager) SwitchAccount(name string) error {
+func (m *Manager) switchAccount(ctx context.Context, name string, confirmed bool) (*config.Account, error) {
 	account, err := m.config.GetAccount(name)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if !confirmed {
+		if err := m.confirm(account); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.runHooks(ctx, HookPreSwitch, account, m.config.ActiveAccount); err != nil {
+		return nil, err
 	}
+	previous := m.config.ActiveAccount
 
+	if err := m.activate(ctx, account); err != nil {
+		return nil, err
+	}
+	m.useKubeContext(account)
+	m.recordSwitch(previous, name)
+	m.record(m.auditEvent(audit.ActionSwitch, account), nil)
+	m.runHooks(ctx, HookPostSwitch, account, previous)
+
+	return account, nil
+}
+
+// activate restores an account's saved ADC and gcloud configuration and
+// makes it the active account, without the side effects of a switch
+// (history, audit, hooks, kubectl context)
+func (m *Manager) activate(ctx context.Context, account *config.Account) error {
 	// Restore ADC
-	if err := adc.RestoreADC(name); err != nil {
+	if err := adc.RestoreADC(ctx, account.Name); err != nil {
 		return err
 	}
+	return m.activateConfig(ctx, account)
+}
 
+// activateConfig activates an account's gcloud configuration and makes it
+// the active account, leaving the default ADC alone
+func (m *Manager) activateConfig(ctx context.Context, account *config.Account) error {
 	// Activate gcloud config
-	if err := gcloud.ActivateConfig(account.ConfigName); err != nil {
+	if err := gcloud.ActivateConfig(ctx, account.ConfigName); err != nil {
 		return err
 	}
 
 	// Ensure project ID is set correctly (in case it was changed manually)
-	if err := gcloud.SetProject(account.ProjectID); err != nil {
+	if err := gcloud.SetProject(ctx, account.ProjectID); err != nil {
 		if strings.Contains(err.Error(), "Reauthentication required") {
-			fmt.Printf("\nWarning: Failed to set project ID because re-authentication is required.\n")
-			fmt.Printf("Please run: gctx login %s\n\n", name)
+			setCredentialStatus(account, config.CredentialNeedsLogin)
+			m.reporter.Warnf("Failed to set project ID because re-authentication is required.")
+			m.reporter.Warnf("Please run: gctx login %s", account.Name)
 		} else {
-			fmt.Printf("Warning: failed to set project ID: %v\n", err)
+			m.reporter.Warnf("failed to set project ID: %v", err)
 		}
 	}
+	m.applyProperties(ctx, account)
 
 	// Update active account
-	m.config.SetActive(name)
+	m.config.ActiveExpiry = m.expiryFor(account)
+	return m.config.SetActive(account.Name)
+}
 
-	fmt.Printf("Switched to account: %s (%s)\n", name, account.ProjectID)
-	return nil
+// recordSwitch appends a switch from previous to name to the history
+func (m *Manager) recordSwitch(previous, name string) {
+	if err := history.Append(history.Entry{
+		Time:   time.Now(),
+		From:   previous,
+		To:     name,
+		Source: m.command,
+	}); err != nil {
+		m.reporter.Warnf("failed to record switch history: %v", err)
+	}
 }
 
-// SaveCredentials manually saves current ADC
-func (m *Manager) SaveCredentials(name string) error {
+// PreviousAccount returns the account that was active before the current one
+func (m *Manager) PreviousAccount() (string, error) {
+	entries, err := history.Load()
+	if err != nil {
+		return "", err
+	}
+
+	previous := history.Previous(entries, m.config.ActiveAccount)
+	if previous == "" {
+		return "", fmt.Errorf("no previous account in switch history")
+	}
+	return previous, nil
+}
+
+// History returns the recorded account switches, most recent first
+func (m *Manager) History() ([]history.Entry, error) {
+	entries, err := history.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(entries)
+	return entries, nil
+}
+
+// SaveCredentials manually saves current ADC and returns the storage path
+func (m *Manager) SaveCredentials(ctx context.Context, name string) (string, error) {
 	account, err := m.config.GetAccount(name)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	adcPath, err := adc.SaveADC(name)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	account.ADCPath = adcPath
-	account.Email, _ = adc.GetADCEmail(adc.GetDefaultADCPath())
+	setCredentialStatus(account, config.CredentialOK)
+	account.Email, _ = adc.GetADCEmail(ctx, adc.GetDefaultADCPath())
 	m.config.Save()
 
-	fmt.Printf("ADC credentials saved for: %s\n", name)
-	fmt.Printf("Location: %s\n", adcPath)
+	if account.QuotaProject != "" {
+		m.writeQuotaProject(account)
+	}
+	m.record(m.auditEvent(audit.ActionSave, account), nil)
 
-	return nil
+	return adcPath, nil
 }
 
-// ListAccounts lists all accounts
-func (m *Manager) ListAccounts() error {
+// ListAccounts returns all accounts sorted by name
+func (m *Manager) ListAccounts() []*config.Account {
 	accounts := m.config.ListAccounts()
-
-	if len(accounts) == 0 {
-		fmt.Println("No accounts configured")
-		return nil
-	}
-
-	fmt.Println("\nConfigured Accounts:")
-	fmt.Println("===================")
-
-	for _, acc := range accounts {
-		active := ""
-		if acc.Name == m.config.ActiveAccount {
-			active = " ← active"
-		}
-
-		email := ""
-		if acc.Email != "" {
-			email = fmt.Sprintf(" [%s]", acc.Email)
-		}
-
-		fmt.Printf("  %s (%s)%s%s\n",
-			acc.Name, acc.ProjectID, email, active)
-	}
-
-	return nil
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Name < accounts[j].Name
+	})
+	return accounts
 }
 
-// GetActiveAccount returns the active account
-func (m *Manager) GetActiveAccount() (string, error) {
-	if m.config.ActiveAccount != "" {
-		return m.config.ActiveAccount, nil
-	}
+// IsActive reports whether name is the active account
+func (m *Manager) IsActive(name string) bool {
+	return name != "" && name == m.config.ActiveAccount
+}
 
-	// Try to detect from current ADC
-	return "unknown", nil
+// ActiveAccount returns the active account, or "" if none is active
+func (m *Manager) ActiveAccount() string {
+	return m.config.ActiveAccount
 }
 
 // DeleteAccount removes an account
-func (m *Manager) DeleteAccount(name string, deleteGcloudConfig bool) error {
+func (m *Manager) DeleteAccount(ctx context.Context, name string, deleteGcloudConfig bool) error {
 	account, err := m.config.GetAccount(name)
 	if err != nil {
 		return err
 	}
+	event := m.auditEvent(audit.ActionDelete, account)
+	previous := m.config.ActiveAccount
+	if err := m.runHooks(ctx, HookPreDelete, account, previous); err != nil {
+		return err
+	}
 
 	// Delete ADC file
 	if account.ADCPath != "" {
 		os.Remove(account.ADCPath)
 	}
+	if cachePath, err := projectsCachePath(name); err == nil {
+		os.Remove(cachePath)
+	}
 
 	// Delete gcloud config if requested
 	if deleteGcloudConfig {
-		cmd := exec.Command("gcloud", "config", "configurations",
-			"delete", account.ConfigName, "--quiet")
-		cmd.Run()
+		gcloud.DeleteConfig(ctx, account.ConfigName)
 	}
 
 	// Remove from config
-	m.config.DeleteAccount(name)
-
-	fmt.Printf("Deleted account: %s\n", name)
+	if err := m.config.DeleteAccount(name); err != nil {
+		return err
+	}
+	m.record(event, nil)
+	m.runHooks(ctx, HookPostDelete, account, previous)
 	return nil
 }
 
 // RunWithAccount runs command with specific account
-func (m *Manager) RunWithAccount(name string, args []string) error {
+func (m *Manager) RunWithAccount(ctx context.Context, name string, args []string) error {
 	// Switch to account
-	if err := m.SwitchAccount(name); err != nil {
+	account, err := m.SwitchAccount(ctx, name)
+	if err != nil {
 		return err
 	}
+	m.reporter.Infof("Switched to account: %s (%s)", name, account.ProjectID)
 
 	// Run command
-	return gcloud.RunCommand(args...)
+	return m.runCommand(ctx, account, args)
 }
 
-// ShowAccountInfo displays detailed account info
-func (m *Manager) ShowAccountInfo(name string) error {
+// runCommand runs a gcloud command under account, recording it in the
+// audit log
+func (m *Manager) runCommand(ctx context.Context, account *config.Account, args []string) error {
+	event := m.auditEvent(audit.ActionRun, account)
+	event.Args = audit.CommandPath(args)
+
+	err := gcloud.RunCommand(ctx, args...)
+	m.record(event, err)
+	return err
+}
+
+// GetAccountInfo returns detailed account info
+func (m *Manager) GetAccountInfo(name string) (*AccountInfo, error) {
 	account, err := m.config.GetAccount(name)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fmt.Printf("\nAccount: %s\n", account.Name)
-	fmt.Println(strings.Repeat("=", 50))
-	fmt.Printf("Project ID:       %s\n", account.ProjectID)
-	fmt.Printf("Config Name:      %s\n", account.ConfigName)
-
-	if account.Email != "" {
-		fmt.Printf("Email:            %s\n", account.Email)
+	info := &AccountInfo{
+		Account: account,
+		Active:  m.IsActive(account.Name),
 	}
 
 	if account.ADCPath != "" {
-		fmt.Printf("ADC Path:         %s\n", account.ADCPath)
-
-		if info, err := os.Stat(account.ADCPath); err == nil {
-			fmt.Printf("ADC Last Modified: %s\n",
-				info.ModTime().Format("2006-01-02 15:04:05"))
+		if stat, err := os.Stat(account.ADCPath); err == nil {
+			info.ADCModTime = stat.ModTime()
 		}
 	}
 
-	fmt.Printf("Created:          %s\n",
-		account.CreatedAt.Format("2006-01-02 15:04:05"))
+	if cred, err := adc.LoadADC(adc.GetStoragePath(account.Name)); err == nil {
+		info.ADCQuotaProject = cred.QuotaProjectID
+	}
+	info.Warnings = m.QuotaProjectWarnings(account)
+
+	return info, nil
+}
 
-	if account.Name == m.config.ActiveAccount {
-		fmt.Println("\nThis is the active account.")
+// DirectoryContext is the account selected by a directory file
+type DirectoryContext struct {
+	File    *dirconfig.File
+	Account *config.Account
+	// Project is the directory's project override, or the account's project
+	Project string
+}
+
+// ResolveDirectory finds the directory file governing dir, walking up to
+// the filesystem root. It returns nil if no directory file applies.
+func (m *Manager) ResolveDirectory(dir string) (*DirectoryContext, error) {
+	file, err := dirconfig.Find(dir)
+	if err != nil || file == nil {
+		return nil, err
 	}
 
-	return nil
+	account, err := m.config.GetAccount(file.Account)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file.Path, err)
+	}
+
+	project := file.Project
+	if project == "" {
+		project = account.ProjectID
+	}
+
+	return &DirectoryContext{
+		File:    file,
+		Account: account,
+		Project: project,
+	}, nil
+}
+
+// AccountEnv returns the environment variables that point gcloud and the
+// client libraries at an account without touching the global active state.
+// An empty project selects the account's own project.
+func (m *Manager) AccountEnv(name, project string) (map[string]string, error) {
+	account, err := m.config.GetAccount(name)
+	if err != nil {
+		return nil, err
+	}
+	return accountEnv(account, project), nil
+}
+
+func accountEnv(account *config.Account, project string) map[string]string {
+	if project == "" {
+		project = account.ProjectID
+	}
+
+	env := map[string]string{
+		"GCTX_ACCOUNT":                account.Name,
+		"CLOUDSDK_ACTIVE_CONFIG_NAME": account.ConfigName,
+		"CLOUDSDK_CORE_PROJECT":       project,
+		"GOOGLE_CLOUD_PROJECT":        project,
+	}
+
+	if storagePath := adc.GetStoragePath(account.Name); fileExists(storagePath) {
+		env["GOOGLE_APPLICATION_CREDENTIALS"] = storagePath
+	}
+
+	return env
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// PromptInfo is the cached state rendered in a shell prompt segment
+type PromptInfo struct {
+	Account    *config.Account
+	Project    string
+	NeedsLogin bool
+}
+
+// PromptInfo returns the prompt state for the named account, or for the
+// active account when name is empty. It reads only gctx's own files and
+// never spawns gcloud, so it is cheap enough to run on every prompt. It
+// returns nil if there is no account to show.
+func (m *Manager) PromptInfo(name, project string) (*PromptInfo, error) {
+	if name == "" {
+		name = m.config.ActiveAccount
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	account, err := m.config.GetAccount(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if project == "" {
+		project = account.ProjectID
+	}
+
+	return &PromptInfo{
+		Account: account,
+		Project: project,
+		NeedsLogin: account.CredentialStatus == config.CredentialNeedsLogin ||
+			!fileExists(adc.GetStoragePath(account.Name)),
+	}, nil
 }
@@ -0,0 +1,179 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/k0wl0n/gctx/pkg/adc"
+	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/gcloud"
+	"github.com/ktr0731/go-fuzzyfinder"
+)
+
+// accountOrActive returns the named account, or the active one if name is empty
+func (m *Manager) accountOrActive(name string) (*config.Account, error) {
+	if name == "" {
+		name = m.config.ActiveAccount
+	}
+	if name == "" {
+		return nil, fmt.Errorf("no active account")
+	}
+	return m.config.GetAccount(name)
+}
+
+// SetProject changes the project of an account (the active account if name
+// is empty). The gcloud core/project property and the ADC quota project are
+// updated, and both the old and new project are remembered as favourites.
+func (m *Manager) SetProject(ctx context.Context, name, projectID string) (*config.Account, error) {
+	account, err := m.accountOrActive(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := gcloud.SetConfigProperty(ctx, account.ConfigName, "core/project", projectID); err != nil {
+		return nil, fmt.Errorf("failed to set project: %w", err)
+	}
+
+	// Remember both projects so it is easy to switch back
+	for _, p := range []string{account.ProjectID, projectID} {
+		if p != "" && !slices.Contains(account.Projects, p) {
+			account.Projects = append(account.Projects, p)
+		}
+	}
+	account.ProjectID = projectID
+
+	// An explicit quota project stays in place when the project changes
+	if account.QuotaProject == "" {
+		m.writeQuotaProject(account)
+	}
+
+	if err := m.config.Save(); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// SetQuotaProject sets the project billed for an account's API usage (the
+// active account if name is empty). It is written into the saved ADC and the
+// gcloud billing/quota_project property. An empty quotaProject reverts to
+// billing the account's project.
+func (m *Manager) SetQuotaProject(ctx context.Context, name, quotaProject string) (*config.Account, error) {
+	account, err := m.accountOrActive(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if quotaProject == "" {
+		err = gcloud.UnsetConfigProperty(ctx, account.ConfigName, "billing/quota_project")
+	} else {
+		err = gcloud.SetConfigProperty(ctx, account.ConfigName, "billing/quota_project", quotaProject)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set quota project: %w", err)
+	}
+
+	account.QuotaProject = quotaProject
+	m.writeQuotaProject(account)
+
+	if err := m.config.Save(); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// writeQuotaProject writes the account's effective quota project into its
+// saved ADC, and into the default ADC if the account is active
+func (m *Manager) writeQuotaProject(account *config.Account) {
+	quotaProject := account.EffectiveQuotaProject()
+
+	if storagePath := adc.GetStoragePath(account.Name); fileExists(storagePath) {
+		if err := adc.SetQuotaProject(storagePath, quotaProject); err != nil {
+			m.reporter.Warnf("failed to update saved ADC quota project: %v", err)
+		}
+	}
+	if m.IsActive(account.Name) && fileExists(adc.GetDefaultADCPath()) {
+		if err := adc.SetQuotaProject(adc.GetDefaultADCPath(), quotaProject); err != nil {
+			m.reporter.Warnf("failed to update ADC quota project: %v", err)
+		}
+	}
+}
+
+// QuotaProjectWarnings reports quota project problems of an account: a saved
+// ADC billing another project than configured, or a quota project that
+// differs from the account's project
+func (m *Manager) QuotaProjectWarnings(account *config.Account) []string {
+	var warnings []string
+	quotaProject := account.EffectiveQuotaProject()
+
+	if cred, err := adc.LoadADC(adc.GetStoragePath(account.Name)); err == nil &&
+		cred.QuotaProjectID != quotaProject {
+		warnings = append(warnings, fmt.Sprintf(
+			"saved ADC bills quota project '%s' instead of '%s' (run: gctx set %s --quota-project %s)",
+			cred.QuotaProjectID, quotaProject, account.Name, quotaProject))
+	}
+
+	if quotaProject != account.ProjectID {
+		warnings = append(warnings, fmt.Sprintf(
+			"quota project '%s' differs from project '%s'; API usage is billed to '%s'",
+			quotaProject, account.ProjectID, quotaProject))
+	}
+
+	return warnings
+}
+
+// ForgetProject removes a favourite project from an account (the active
+// account if name is empty). The project in use cannot be removed.
+func (m *Manager) ForgetProject(name, projectID string) error {
+	account, err := m.accountOrActive(name)
+	if err != nil {
+		return err
+	}
+
+	if projectID == account.ProjectID {
+		return fmt.Errorf("project '%s' is in use by '%s'", projectID, account.Name)
+	}
+
+	idx := slices.Index(account.Projects, projectID)
+	if idx < 0 {
+		return fmt.Errorf("project '%s' is not a favourite of '%s'", projectID, account.Name)
+	}
+	account.Projects = slices.Delete(account.Projects, idx, idx+1)
+
+	return m.config.Save()
+}
+
+// SelectProjectInteractive launches an interactive UI to select one of an
+// account's projects (the active account if name is empty). Favourite
+// projects come first, followed by other projects the account can access.
+func (m *Manager) SelectProjectInteractive(ctx context.Context, name string) (string, error) {
+	account, err := m.accountOrActive(name)
+	if err != nil {
+		return "", err
+	}
+
+	projects := account.AllProjects()
+	discovered, err := m.DiscoverProjects(ctx, account.Name, false)
+	if err != nil {
+		m.reporter.Warnf("could not discover projects: %v", err)
+	}
+	for _, p := range discovered {
+		if !slices.Contains(projects, p.ProjectID) {
+			projects = append(projects, p.ProjectID)
+		}
+	}
+	idx, err := fuzzyfinder.Find(
+		projects,
+		func(i int) string {
+			if projects[i] == account.ProjectID {
+				return projects[i] + " (current)"
+			}
+			return projects[i]
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return projects[idx], nil
+}
@@ -0,0 +1,82 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/gcloud"
+)
+
+// managedProperties are gcloud properties gctx maintains through dedicated
+// account settings
+var managedProperties = map[string]string{
+	"core/project":          "gctx project",
+	"billing/quota_project": "gctx set --quota-project",
+}
+
+// ValidateProperty checks that a gcloud property name can be stored on an
+// account
+func ValidateProperty(property string) error {
+	section, name, ok := strings.Cut(property, "/")
+	if !ok || section == "" || name == "" || strings.ContainsAny(property, " =") {
+		return fmt.Errorf("invalid gcloud property %q, expected section/name (e.g. compute/region)", property)
+	}
+	if alternative, ok := managedProperties[property]; ok {
+		return fmt.Errorf("%s is managed by gctx, use '%s' instead", property, alternative)
+	}
+	return nil
+}
+
+// SetProperties stores gcloud properties on an account and applies them to
+// its gcloud configuration. An empty value removes the property.
+func (m *Manager) SetProperties(ctx context.Context, name string, props map[string]string) (*config.Account, error) {
+	account, err := m.config.GetAccount(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for property := range props {
+		if err := ValidateProperty(property); err != nil {
+			return nil, err
+		}
+	}
+
+	for property, value := range props {
+		if value == "" {
+			if err := gcloud.UnsetConfigProperty(ctx, account.ConfigName, property); err != nil {
+				return nil, fmt.Errorf("failed to unset %s: %w", property, err)
+			}
+			delete(account.Properties, property)
+			continue
+		}
+
+		if err := gcloud.SetConfigProperty(ctx, account.ConfigName, property, value); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", property, err)
+		}
+		if account.Properties == nil {
+			account.Properties = make(map[string]string)
+		}
+		account.Properties[property] = value
+	}
+
+	if len(account.Properties) == 0 {
+		account.Properties = nil
+	}
+
+	if err := m.config.Save(); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// applyProperties re-applies an account's stored properties to its gcloud
+// configuration, in case they were changed manually
+func (m *Manager) applyProperties(ctx context.Context, account *config.Account) {
+	for property, value := range account.Properties {
+		if err := gcloud.SetConfigProperty(ctx, account.ConfigName, property, value); err != nil {
+			m.reporter.Warnf("failed to set %s: %v", property, err)
+		}
+	}
+}
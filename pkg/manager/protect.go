@@ -0,0 +1,155 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+)
+
+// Confirmer approves switching to a protected account. It returns an error
+// if the user declines or cannot be asked.
+type Confirmer interface {
+	ConfirmAccount(account *config.Account) error
+}
+
+// WithConfirmer sets the confirmer consulted before using protected accounts.
+// Without one, protected accounts cannot be switched to.
+func WithConfirmer(c Confirmer) Option {
+	return func(m *Manager) {
+		m.confirmer = c
+	}
+}
+
+func (m *Manager) confirm(account *config.Account) error {
+	if !account.Protected {
+		return nil
+	}
+	if m.confirmer == nil {
+		return fmt.Errorf("account '%s' is protected and requires confirmation", account.Name)
+	}
+	return m.confirmer.ConfirmAccount(account)
+}
+
+// SetProtected marks or unmarks an account as protected
+func (m *Manager) SetProtected(name string, protected bool) error {
+	account, err := m.config.GetAccount(name)
+	if err != nil {
+		return err
+	}
+
+	account.Protected = protected
+	return m.config.Save()
+}
+
+// SetExpireAfter sets how long a switch to a protected account lasts before
+// reverting to the default account. Zero disables the expiry.
+func (m *Manager) SetExpireAfter(name string, d time.Duration) error {
+	account, err := m.config.GetAccount(name)
+	if err != nil {
+		return err
+	}
+
+	account.ExpireAfter = config.Duration(d)
+	return m.config.Save()
+}
+
+// SetDefaultAccount sets the safe account that expired switches revert to
+func (m *Manager) SetDefaultAccount(name string) error {
+	if _, err := m.config.GetAccount(name); err != nil {
+		return err
+	}
+
+	m.config.DefaultAccount = name
+	return m.config.Save()
+}
+
+// DefaultAccount returns the safe default account, or "" if none is set
+func (m *Manager) DefaultAccount() string {
+	return m.config.DefaultAccount
+}
+
+// ActiveExpiry returns the pending expiry of the active account, if any
+func (m *Manager) ActiveExpiry() *config.Expiry {
+	return m.config.ActiveExpiry
+}
+
+// RevertExpired switches back once the active account's expiry has passed.
+// It returns the account switched to, or nil if nothing was due.
+func (m *Manager) RevertExpired(ctx context.Context) (*config.Account, error) {
+	expiry := m.config.ActiveExpiry
+	if expiry == nil || time.Now().Before(expiry.At) {
+		return nil, nil
+	}
+
+	expired := m.config.ActiveAccount
+	if _, err := m.config.GetAccount(expiry.RevertTo); err != nil {
+		// Retrying can never succeed, so give up on the expiry
+		m.config.ActiveExpiry = nil
+		if saveErr := m.config.Save(); saveErr != nil {
+			return nil, saveErr
+		}
+		return nil, fmt.Errorf("switch to '%s' expired but cannot revert: %w", expired, err)
+	}
+
+	// The expiry is kept until the switch succeeds (which replaces it), so
+	// that a failed revert is retried on the next invocation
+	account, err := m.switchAccount(ctx, expiry.RevertTo, true)
+	if err != nil {
+		return nil, fmt.Errorf("switch to '%s' expired but reverting to '%s' failed: %w", expired, expiry.RevertTo, err)
+	}
+
+	m.reporter.Infof("Switch to '%s' expired, reverted to: %s", expired, account.Name)
+	return account, nil
+}
+
+// SwitchAccountFor switches to an account for a limited time. Once d has
+// passed, RevertExpired restores the account that was active before (or the
+// default account if none was).
+func (m *Manager) SwitchAccountFor(ctx context.Context, name string, d time.Duration) (*config.Account, error) {
+	if d <= 0 {
+		return nil, fmt.Errorf("switch duration must be positive, got %s", d)
+	}
+
+	revertTo := m.config.ActiveAccount
+	if expiry := m.config.ActiveExpiry; expiry != nil {
+		// Extending a time-boxed switch keeps the original way back
+		revertTo = expiry.RevertTo
+	}
+	if revertTo == "" || revertTo == name {
+		revertTo = m.config.DefaultAccount
+	}
+	if revertTo == "" || revertTo == name {
+		return nil, fmt.Errorf("no previous or default account to revert to")
+	}
+
+	account, err := m.SwitchAccount(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	m.config.ActiveExpiry = &config.Expiry{
+		At:       time.Now().Add(d),
+		RevertTo: revertTo,
+	}
+	if err := m.config.Save(); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// expiryFor returns the expiry to record when switching to account
+func (m *Manager) expiryFor(account *config.Account) *config.Expiry {
+	target := m.config.DefaultAccount
+	if !account.Protected || account.ExpireAfter <= 0 ||
+		target == "" || target == account.Name {
+		return nil
+	}
+
+	return &config.Expiry{
+		At:       time.Now().Add(time.Duration(account.ExpireAfter)),
+		RevertTo: target,
+	}
+}
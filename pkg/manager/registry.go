@@ -0,0 +1,70 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/k0wl0n/gctx/pkg/oauth"
+)
+
+// RegistryUsername is the user name registries expect along with an OAuth
+// access token
+const RegistryUsername = "oauth2accesstoken"
+
+// ErrRegistryNotLinked is returned for registries no account is linked to
+var ErrRegistryNotLinked = errors.New("registry not linked to an account")
+
+// RegistryHost reduces a registry server URL as passed by docker (e.g.
+// "https://europe-docker.pkg.dev/v1/") to its host
+func RegistryHost(serverURL string) string {
+	serverURL = strings.TrimSpace(serverURL)
+	if !strings.Contains(serverURL, "://") {
+		serverURL = "https://" + serverURL
+	}
+	if u, err := url.Parse(serverURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return serverURL
+}
+
+// LinkRegistries makes an account provide credentials for registry hosts
+func (m *Manager) LinkRegistries(name string, hosts []string) error {
+	if _, err := m.config.GetAccount(name); err != nil {
+		return err
+	}
+
+	if m.config.Registries == nil {
+		m.config.Registries = make(map[string]string)
+	}
+	for _, host := range hosts {
+		m.config.Registries[RegistryHost(host)] = name
+	}
+	return m.config.Save()
+}
+
+// UnlinkRegistries removes the account links of registry hosts
+func (m *Manager) UnlinkRegistries(hosts []string) error {
+	for _, host := range hosts {
+		delete(m.config.Registries, RegistryHost(host))
+	}
+	return m.config.Save()
+}
+
+// Registries returns the linked registry hosts and their accounts
+func (m *Manager) Registries() map[string]string {
+	return m.config.Registries
+}
+
+// RegistryToken mints an access token for a registry from the saved
+// credentials of the account linked to it, independent of the active account
+func (m *Manager) RegistryToken(ctx context.Context, serverURL string) (*oauth.Token, error) {
+	host := RegistryHost(serverURL)
+	name, ok := m.config.Registries[host]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRegistryNotLinked, host)
+	}
+	return m.AccessToken(ctx, name)
+}
@@ -0,0 +1,195 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/adc"
+	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/gcloud"
+	"github.com/k0wl0n/gctx/pkg/history"
+)
+
+// RenameAccount renames an account, keeping its saved ADC and creation
+// time. With renameGcloudConfig, the gcloud configuration is recreated as
+// "<newName>-config" with the same properties and the old one is deleted.
+func (m *Manager) RenameAccount(ctx context.Context, oldName, newName string, renameGcloudConfig bool) (*config.Account, error) {
+	account, err := m.config.GetAccount(oldName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.config.GetAccount(newName); err == nil {
+		return nil, fmt.Errorf("account '%s' already exists", newName)
+	}
+
+	oldConfigName := account.ConfigName
+	oldADCPath := account.ADCPath
+	newConfigName := fmt.Sprintf("%s-config", newName)
+	if newConfigName == oldConfigName {
+		renameGcloudConfig = false
+	}
+	if renameGcloudConfig {
+		if err := checkNewConfig(ctx, newConfigName); err != nil {
+			return nil, err
+		}
+	}
+
+	// Each step undoes the previous ones if it fails, so that a failed
+	// rename leaves neither a moved ADC nor an orphan gcloud configuration
+	if account.ADCPath != "" {
+		adcPath, err := adc.MoveStorage(oldName, newName)
+		if err != nil {
+			return nil, err
+		}
+		account.ADCPath = adcPath
+	}
+	createdConfig := false
+	undo := func() {
+		if createdConfig {
+			gcloud.DeleteConfig(context.WithoutCancel(ctx), newConfigName)
+		}
+		if oldADCPath != "" {
+			if _, err := adc.MoveStorage(newName, oldName); err != nil {
+				m.reporter.Warnf("failed to move saved ADC back to '%s': %v", oldName, err)
+			}
+		}
+		account.ADCPath = oldADCPath
+		account.ConfigName = oldConfigName
+	}
+
+	if renameGcloudConfig {
+		createdConfig = true
+		if err := gcloud.CopyConfig(ctx, oldConfigName, newConfigName); err != nil {
+			undo()
+			return nil, err
+		}
+		m.reporter.Infof("Copied gcloud configuration: %s -> %s", oldConfigName, newConfigName)
+		account.ConfigName = newConfigName
+	}
+
+	if err := m.config.RenameAccount(oldName, newName); err != nil {
+		undo()
+		return nil, err
+	}
+
+	if oldCache, err := projectsCachePath(oldName); err == nil {
+		if newCache, err := projectsCachePath(newName); err == nil {
+			if err := os.Rename(oldCache, newCache); err != nil && !os.IsNotExist(err) {
+				m.reporter.Warnf("failed to move projects cache: %v", err)
+			}
+		}
+	}
+	if err := history.Rename(oldName, newName); err != nil {
+		m.reporter.Warnf("failed to update switch history: %v", err)
+	}
+
+	if renameGcloudConfig {
+		// gcloud refuses to delete the active configuration
+		if m.IsActive(newName) {
+			if err := gcloud.ActivateConfig(ctx, newConfigName); err != nil {
+				return nil, err
+			}
+		}
+		if err := gcloud.DeleteConfig(ctx, oldConfigName); err != nil {
+			m.reporter.Warnf("failed to delete old gcloud configuration: %v", err)
+		}
+		if account.KubeContext != "" {
+			m.reporter.Warnf("kubectl context '%s' may still authenticate with %s (run: gctx kube link %s %s --pin-auth)",
+				account.KubeContext, oldConfigName, newName, account.KubeContext)
+		}
+	}
+
+	return account, nil
+}
+
+// CloneAccount creates dst reusing src's saved credentials and gcloud
+// properties, with projectID as its project (src's project if empty)
+func (m *Manager) CloneAccount(ctx context.Context, src, dst, projectID string) (*config.Account, error) {
+	source, err := m.config.GetAccount(src)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.config.GetAccount(dst); err == nil {
+		return nil, fmt.Errorf("account '%s' already exists", dst)
+	}
+
+	if projectID == "" {
+		projectID = source.ProjectID
+	}
+
+	configName := fmt.Sprintf("%s-config", dst)
+	if err := checkNewConfig(ctx, configName); err != nil {
+		return nil, err
+	}
+
+	// Leave neither the gcloud configuration nor copied ADC behind if a
+	// later step fails
+	copiedADC := false
+	undo := func() {
+		gcloud.DeleteConfig(context.WithoutCancel(ctx), configName)
+		if copiedADC {
+			os.Remove(adc.GetStoragePath(dst))
+		}
+	}
+
+	if err := gcloud.CopyConfig(ctx, source.ConfigName, configName); err != nil {
+		undo()
+		return nil, err
+	}
+	if err := gcloud.SetConfigProperty(ctx, configName, "core/project", projectID); err != nil {
+		undo()
+		return nil, err
+	}
+	m.reporter.Infof("Created gcloud configuration: %s", configName)
+
+	account := &config.Account{
+		Name:                dst,
+		ConfigName:          configName,
+		ProjectID:           projectID,
+		CreatedAt:           time.Now(),
+		Email:               source.Email,
+		Protected:           source.Protected,
+		ExpireAfter:         source.ExpireAfter,
+		CredentialStatus:    source.CredentialStatus,
+		CredentialCheckedAt: source.CredentialCheckedAt,
+		Tags:                maps.Clone(source.Tags),
+		QuotaProject:        source.QuotaProject,
+		Properties:          maps.Clone(source.Properties),
+	}
+
+	if source.ADCPath != "" {
+		adcPath, err := adc.CopyStorage(src, dst)
+		if err != nil {
+			undo()
+			return nil, err
+		}
+		copiedADC = true
+		account.ADCPath = adcPath
+	}
+
+	if err := m.config.AddAccount(account); err != nil {
+		delete(m.config.Accounts, dst)
+		undo()
+		return nil, err
+	}
+	m.writeQuotaProject(account)
+
+	return account, nil
+}
+
+// checkNewConfig refuses a gcloud configuration name that is already taken,
+// since copying into it would overwrite its properties
+func checkNewConfig(ctx context.Context, name string) error {
+	configs, err := gcloud.ListConfigs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list gcloud configurations: %w", err)
+	}
+	if slices.Contains(configs, name) {
+		return fmt.Errorf("gcloud configuration '%s' already exists", name)
+	}
+	return nil
+}
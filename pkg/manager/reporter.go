@@ -0,0 +1,46 @@
+package manager
+
+import "io"
+
+// Reporter receives progress messages emitted while a Manager operation runs.
+// Results are returned to the caller; the reporter only sees the narrative in
+// between (e.g. "Running authentication...") and non-fatal warnings.
+type Reporter interface {
+	Infof(format string, args ...any)
+	Warnf(format string, args ...any)
+}
+
+// Option configures a Manager created with New
+type Option func(*Manager)
+
+// WithReporter sets the reporter used for progress messages
+func WithReporter(r Reporter) Option {
+	return func(m *Manager) {
+		if r != nil {
+			m.reporter = r
+		}
+	}
+}
+
+// WithCommand names the CLI command driving the manager; it is recorded
+// alongside switches in the history
+func WithCommand(name string) Option {
+	return func(m *Manager) {
+		m.command = name
+	}
+}
+
+// WithHookOutput sets where the standard output of hooks goes (standard
+// error by default, so that it never mixes with a command's output)
+func WithHookOutput(w io.Writer) Option {
+	return func(m *Manager) {
+		if w != nil {
+			m.hookOutput = w
+		}
+	}
+}
+
+type nopReporter struct{}
+
+func (nopReporter) Infof(string, ...any) {}
+func (nopReporter) Warnf(string, ...any) {}
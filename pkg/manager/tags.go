@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/selector"
+)
+
+// SelectAccounts returns the accounts whose tags match sel, sorted by name
+func (m *Manager) SelectAccounts(sel selector.Selector) []*config.Account {
+	var matched []*config.Account
+	for _, acc := range m.ListAccounts() {
+		if sel.Matches(acc.Tags) {
+			matched = append(matched, acc)
+		}
+	}
+	return matched
+}
+
+// AddTags sets tags on an account, overwriting existing values
+func (m *Manager) AddTags(name string, tags map[string]string) error {
+	account, err := m.config.GetAccount(name)
+	if err != nil {
+		return err
+	}
+
+	if account.Tags == nil {
+		account.Tags = make(map[string]string, len(tags))
+	}
+	for key, value := range tags {
+		if err := selector.ValidateKey(key); err != nil {
+			return err
+		}
+		account.Tags[key] = value
+	}
+
+	return m.config.Save()
+}
+
+// RemoveTags removes tags from an account by key
+func (m *Manager) RemoveTags(name string, keys []string) error {
+	account, err := m.config.GetAccount(name)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if _, ok := account.Tags[key]; !ok {
+			return fmt.Errorf("account '%s' has no tag '%s'", name, key)
+		}
+		delete(account.Tags, key)
+	}
+	if len(account.Tags) == 0 {
+		account.Tags = nil
+	}
+
+	return m.config.Save()
+}
+
+// RunWithSelector runs a gcloud command once for every account matching sel,
+// then switches back to the account that was active before. It stops at the
+// first failure or once ctx is cancelled.
+func (m *Manager) RunWithSelector(ctx context.Context, sel selector.Selector, args []string) error {
+	accounts := m.SelectAccounts(sel)
+	if len(accounts) == 0 {
+		return fmt.Errorf("no accounts match selector '%s'", sel)
+	}
+
+	original := m.config.ActiveAccount
+	defer func() {
+		if original == "" || original == m.config.ActiveAccount {
+			return
+		}
+		// Switch back even after Ctrl-C or a timeout, like a rollback
+		restoreCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+		defer cancel()
+		if _, err := m.switchAccount(restoreCtx, original, true); err != nil {
+			m.reporter.Warnf("failed to switch back to '%s': %v", original, err)
+		}
+	}()
+
+	for _, acc := range accounts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := m.SwitchAccount(ctx, acc.Name); err != nil {
+			return err
+		}
+		m.reporter.Infof("==> %s (%s)", acc.Name, acc.ProjectID)
+
+		if err := m.runCommand(ctx, acc, args); err != nil {
+			return fmt.Errorf("%s: %w", acc.Name, err)
+		}
+	}
+
+	return nil
+}
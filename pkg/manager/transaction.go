@@ -0,0 +1,203 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/k0wl0n/gctx/pkg/adc"
+	"github.com/k0wl0n/gctx/pkg/config"
+	"github.com/k0wl0n/gctx/pkg/gcloud"
+)
+
+// ErrInterrupted is returned when an operation is cancelled with Ctrl-C
+var ErrInterrupted = errors.New("interrupted")
+
+// rollbackTimeout bounds the rollback, which still has to run once the
+// operation's context is done
+const rollbackTimeout = 30 * time.Second
+
+// transaction records the state touched by login and create so that a
+// failed or interrupted flow can be rolled back completely
+type transaction struct {
+	m   *Manager
+	ctx context.Context
+
+	activeAccount string
+	activeExpiry  *config.Expiry
+	gcloudConfig  string
+	// defaultADC is the content of the default ADC file, nil if absent
+	defaultADC []byte
+
+	// Set by create
+	createdAccount string
+	createdConfig  string
+	// modifiedConfig is an existing gcloud configuration that create
+	// reuses, whose properties were configProps before
+	modifiedConfig string
+	configProps    map[string]string
+}
+
+// begin snapshots the active account, gcloud configuration and default ADC.
+// Cancelling ctx kills running gcloud children, which fails the flow and
+// triggers the rollback.
+func (m *Manager) begin(ctx context.Context) (*transaction, error) {
+	tx := &transaction{
+		m:             m,
+		ctx:           ctx,
+		activeAccount: m.config.ActiveAccount,
+		activeExpiry:  m.config.ActiveExpiry,
+	}
+
+	gcloudConfig, err := gcloud.GetActiveConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx.gcloudConfig = gcloudConfig
+
+	data, err := os.ReadFile(adc.GetDefaultADCPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	tx.defaultADC = data
+
+	return tx, nil
+}
+
+// check returns ErrInterrupted once Ctrl-C has been pressed, or the context
+// error if the operation timed out
+func (tx *transaction) check() error {
+	switch err := tx.ctx.Err(); {
+	case errors.Is(err, context.Canceled):
+		return ErrInterrupted
+	default:
+		return err
+	}
+}
+
+// end rolls back everything recorded if err is non-nil or the operation was
+// interrupted, and returns the cause
+func (tx *transaction) end(err error) error {
+	if ctxErr := tx.check(); ctxErr != nil {
+		// The failure of a killed gcloud child only hides the cause
+		err = ctxErr
+	}
+	if err != nil {
+		tx.rollback()
+	}
+	return err
+}
+
+func (tx *transaction) rollback() {
+	m := tx.m
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(tx.ctx), rollbackTimeout)
+	defer cancel()
+	m.reporter.Warnf("rolling back changes")
+
+	if name := tx.createdAccount; name != "" {
+		if _, err := m.config.GetAccount(name); err == nil {
+			m.config.DeleteAccount(name)
+		}
+		os.Remove(adc.GetStoragePath(name))
+		if cachePath, err := projectsCachePath(name); err == nil {
+			os.Remove(cachePath)
+		}
+	}
+
+	m.config.ActiveAccount = tx.activeAccount
+	m.config.ActiveExpiry = tx.activeExpiry
+	if err := m.config.Save(); err != nil {
+		m.reporter.Warnf("failed to restore active account: %v", err)
+	}
+
+	if err := tx.restoreDefaultADC(); err != nil {
+		m.reporter.Warnf("failed to restore ADC: %v", err)
+	}
+
+	if tx.gcloudConfig != "" {
+		if err := gcloud.ActivateConfig(ctx, tx.gcloudConfig); err != nil {
+			m.reporter.Warnf("failed to reactivate gcloud configuration %s: %v", tx.gcloudConfig, err)
+		}
+	}
+
+	if tx.modifiedConfig != "" {
+		if err := tx.restoreConfig(ctx); err != nil {
+			m.reporter.Warnf("failed to restore gcloud configuration %s: %v", tx.modifiedConfig, err)
+		}
+	}
+
+	if tx.createdConfig != "" && tx.createdConfig != tx.gcloudConfig {
+		if err := gcloud.DeleteConfig(ctx, tx.createdConfig); err != nil {
+			m.reporter.Warnf("failed to delete gcloud configuration %s: %v", tx.createdConfig, err)
+		}
+	}
+}
+
+// snapshotConfig records the properties of an existing gcloud configuration
+// about to be modified, so that rollback can restore them
+func (tx *transaction) snapshotConfig(name string) error {
+	props, err := gcloud.GetConfigProperties(tx.ctx, name)
+	if err != nil {
+		return err
+	}
+	tx.modifiedConfig = name
+	tx.configProps = props
+	return nil
+}
+
+// restoreConfig sets the properties of the modified gcloud configuration
+// back to their snapshot
+func (tx *transaction) restoreConfig(ctx context.Context) error {
+	current, err := gcloud.GetConfigProperties(ctx, tx.modifiedConfig)
+	if err != nil {
+		return err
+	}
+
+	for property := range current {
+		if _, ok := tx.configProps[property]; !ok {
+			if err := gcloud.UnsetConfigProperty(ctx, tx.modifiedConfig, property); err != nil {
+				return err
+			}
+		}
+	}
+	for property, value := range tx.configProps {
+		if current[property] == value {
+			continue
+		}
+		if err := gcloud.SetConfigProperty(ctx, tx.modifiedConfig, property, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *transaction) restoreDefaultADC() error {
+	defaultPath := adc.GetDefaultADCPath()
+	if tx.defaultADC == nil {
+		err := os.Remove(defaultPath)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	// Atomic write: temp file -> rename
+	tempPath := defaultPath + ".tmp"
+	if err := os.WriteFile(tempPath, tx.defaultADC, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tempPath, defaultPath); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return nil
+}
+
+// configExists reports whether a gcloud configuration already exists; on
+// error it assumes it does, so that rollback never deletes it
+func configExists(ctx context.Context, name string) bool {
+	configs, err := gcloud.ListConfigs(ctx)
+	return err != nil || slices.Contains(configs, name)
+}
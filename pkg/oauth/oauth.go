@@ -0,0 +1,92 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTokenEndpoint is Google's OAuth 2.0 token endpoint
+const DefaultTokenEndpoint = "https://oauth2.googleapis.com/token"
+
+// ErrReauthRequired is returned when the refresh token has expired or been
+// revoked and the user has to log in again
+var ErrReauthRequired = errors.New("re-authentication required")
+
+// Token is an access token minted from a refresh token
+type Token struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	Expiry      time.Time `json:"-"`
+}
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// RefreshToken exchanges a refresh token for an access token at endpoint
+// (DefaultTokenEndpoint if empty)
+func RefreshToken(ctx context.Context, endpoint, clientID, clientSecret, refreshToken string) (*Token, error) {
+	if endpoint == "" {
+		endpoint = DefaultTokenEndpoint
+	}
+	if refreshToken == "" {
+		return nil, fmt.Errorf("credentials have no refresh token")
+	}
+
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"refresh_token": {refreshToken},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		json.Unmarshal(body, &failure)
+		if failure.Error == "invalid_grant" {
+			return nil, fmt.Errorf("%w: %s", ErrReauthRequired, failure.ErrorDescription)
+		}
+		return nil, fmt.Errorf("token refresh failed: %s: %s",
+			resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var token struct {
+		Token
+		ExpiresIn int `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access token")
+	}
+
+	token.Token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return &token.Token, nil
+}
@@ -0,0 +1,95 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRefreshToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+			return
+		}
+		for key, want := range map[string]string{
+			"grant_type":    "refresh_token",
+			"client_id":     "id",
+			"client_secret": "secret",
+			"refresh_token": "refresh",
+		} {
+			if got := r.PostForm.Get(key); got != want {
+				t.Errorf("%s = %q, want %q", key, got, want)
+			}
+		}
+		w.Write([]byte(`{"access_token":"tok","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	token, err := RefreshToken(context.Background(), srv.URL, "id", "secret", "refresh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "tok" || token.TokenType != "Bearer" {
+		t.Errorf("token = %+v", token)
+	}
+	if until := time.Until(token.Expiry); until < 59*time.Minute || until > time.Hour {
+		t.Errorf("expiry in %s, want about 1h", until)
+	}
+}
+
+func TestRefreshTokenInvalidGrant(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant","error_description":"Token has been expired or revoked."}`))
+	}))
+	defer srv.Close()
+
+	_, err := RefreshToken(context.Background(), srv.URL, "id", "secret", "refresh")
+	if !errors.Is(err, ErrReauthRequired) {
+		t.Fatalf("err = %v, want ErrReauthRequired", err)
+	}
+}
+
+func TestRefreshTokenErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{name: "server error", status: http.StatusInternalServerError, body: `oops`},
+		{name: "other oauth error", status: http.StatusUnauthorized, body: `{"error":"invalid_client"}`},
+		{name: "no access token", status: http.StatusOK, body: `{"token_type":"Bearer"}`},
+		{name: "invalid json", status: http.StatusOK, body: `{`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			_, err := RefreshToken(context.Background(), srv.URL, "id", "secret", "refresh")
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if errors.Is(err, ErrReauthRequired) {
+				t.Errorf("err = %v, must not require re-authentication", err)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenWithoutRefreshToken(t *testing.T) {
+	if _, err := RefreshToken(context.Background(), "http://127.0.0.1:0", "id", "secret", ""); err == nil {
+		t.Fatal("expected an error")
+	}
+}
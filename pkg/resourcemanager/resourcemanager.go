@@ -0,0 +1,95 @@
+package resourcemanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultEndpoint is the Cloud Resource Manager API
+const DefaultEndpoint = "https://cloudresourcemanager.googleapis.com"
+
+// Project is a project visible to the caller
+type Project struct {
+	ProjectID string `json:"projectId"`
+	Name      string `json:"name"`
+	State     string `json:"lifecycleState"`
+}
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// ListProjects returns the active projects the access token can see, sorted
+// by project ID. endpoint defaults to DefaultEndpoint.
+func ListProjects(ctx context.Context, endpoint, accessToken string) ([]Project, error) {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	endpoint = strings.TrimSuffix(endpoint, "/")
+
+	var projects []Project
+	pageToken := ""
+	for {
+		query := url.Values{"filter": {"lifecycleState:ACTIVE"}}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+			endpoint+"/v1/projects?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+
+		page, err := fetchPage(req)
+		if err != nil {
+			return nil, err
+		}
+
+		projects = append(projects, page.Projects...)
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].ProjectID < projects[j].ProjectID
+	})
+	return projects, nil
+}
+
+type listResponse struct {
+	Projects      []Project `json:"projects"`
+	NextPageToken string    `json:"nextPageToken"`
+}
+
+func fetchPage(req *http.Request) (*listResponse, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("listing projects failed: %s: %s",
+			resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var page listResponse
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
@@ -0,0 +1,66 @@
+package resourcemanager
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestListProjectsPagination(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"projects":[{"projectId":"c","name":"C","lifecycleState":"ACTIVE"}],"nextPageToken":"p2"}`,
+		"p2": `{"projects":[{"projectId":"a","name":"A","lifecycleState":"ACTIVE"}],"nextPageToken":"p3"}`,
+		"p3": `{"projects":[{"projectId":"b","name":"B","lifecycleState":"ACTIVE"}]}`,
+	}
+	requests := 0
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/v1/projects" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if got := r.URL.Query().Get("filter"); got != "lifecycleState:ACTIVE" {
+			t.Errorf("filter = %q", got)
+		}
+		page, ok := pages[r.URL.Query().Get("pageToken")]
+		if !ok {
+			t.Errorf("unexpected page token %q", r.URL.Query().Get("pageToken"))
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(page))
+	}))
+	defer srv.Close()
+
+	projects, err := ListProjects(context.Background(), srv.URL+"/", "tok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 3 {
+		t.Errorf("requests = %d, want 3", requests)
+	}
+
+	var ids []string
+	for _, p := range projects {
+		ids = append(ids, p.ProjectID)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("projects = %v, want %v", ids, want)
+	}
+}
+
+func TestListProjectsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"status":"PERMISSION_DENIED"}}`, http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	if _, err := ListProjects(context.Background(), srv.URL, "tok"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
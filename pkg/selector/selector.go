@@ -0,0 +1,150 @@
+package selector
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Operators supported in a requirement
+const (
+	Equals    = "="
+	NotEquals = "!="
+	Exists    = "exists"
+	NotExists = "!exists"
+)
+
+// Requirement is a single condition on an account's tags
+type Requirement struct {
+	Key      string
+	Operator string
+	Value    string
+}
+
+// Selector is a conjunction of requirements. A nil Selector matches everything.
+type Selector []Requirement
+
+// Parse parses a comma separated selector such as
+//
+//	env=prod,team!=data,client,!legacy
+//
+// where "key" requires the tag to be present and "!key" requires it to be absent
+func Parse(s string) (Selector, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+
+	var sel Selector
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+
+		var req Requirement
+		switch {
+		case strings.Contains(part, "!="):
+			key, value, _ := strings.Cut(part, "!=")
+			req = Requirement{Key: key, Operator: NotEquals, Value: value}
+		case strings.Contains(part, "="):
+			key, value, _ := strings.Cut(part, "=")
+			req = Requirement{Key: key, Operator: Equals, Value: value}
+		case strings.HasPrefix(part, "!"):
+			req = Requirement{Key: part[1:], Operator: NotExists}
+		default:
+			req = Requirement{Key: part, Operator: Exists}
+		}
+
+		req.Key = strings.TrimSpace(req.Key)
+		req.Value = strings.TrimSpace(req.Value)
+		if err := ValidateKey(req.Key); err != nil {
+			return nil, fmt.Errorf("invalid selector %q: %w", part, err)
+		}
+		sel = append(sel, req)
+	}
+
+	return sel, nil
+}
+
+// Matches reports whether tags satisfy every requirement
+func (sel Selector) Matches(tags map[string]string) bool {
+	for _, req := range sel {
+		value, ok := tags[req.Key]
+		switch req.Operator {
+		case Equals:
+			if !ok || value != req.Value {
+				return false
+			}
+		case NotEquals:
+			if ok && value == req.Value {
+				return false
+			}
+		case Exists:
+			if !ok {
+				return false
+			}
+		case NotExists:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (sel Selector) String() string {
+	parts := make([]string, len(sel))
+	for i, req := range sel {
+		switch req.Operator {
+		case Exists:
+			parts[i] = req.Key
+		case NotExists:
+			parts[i] = "!" + req.Key
+		default:
+			parts[i] = req.Key + req.Operator + req.Value
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// ValidateKey checks that a tag key can be used in a selector
+func ValidateKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("empty tag key")
+	}
+	if strings.ContainsAny(key, "=!, ") {
+		return fmt.Errorf("tag key %q must not contain '=', '!', ',' or spaces", key)
+	}
+	return nil
+}
+
+// ParseTag parses a "key=value" (or bare "key") tag
+func ParseTag(s string) (string, string, error) {
+	key, value, _ := strings.Cut(s, "=")
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
+	if err := ValidateKey(key); err != nil {
+		return "", "", err
+	}
+	if strings.Contains(value, ",") {
+		return "", "", fmt.Errorf("tag value %q must not contain ','", value)
+	}
+	return key, value, nil
+}
+
+// FormatTags renders tags as a sorted "k=v,k2=v2" list
+func FormatTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		if tags[key] == "" {
+			parts[i] = key
+		} else {
+			parts[i] = key + "=" + tags[key]
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,136 @@
+package selector
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Selector
+		wantErr bool
+	}{
+		{in: "", want: nil},
+		{in: "  ", want: nil},
+		{in: "env=prod", want: Selector{{Key: "env", Operator: Equals, Value: "prod"}}},
+		{in: "team!=data", want: Selector{{Key: "team", Operator: NotEquals, Value: "data"}}},
+		{in: "client", want: Selector{{Key: "client", Operator: Exists}}},
+		{in: "!legacy", want: Selector{{Key: "legacy", Operator: NotExists}}},
+		{in: "env=", want: Selector{{Key: "env", Operator: Equals, Value: ""}}},
+		{
+			in: " env = prod , team!=data,client, !legacy ",
+			want: Selector{
+				{Key: "env", Operator: Equals, Value: "prod"},
+				{Key: "team", Operator: NotEquals, Value: "data"},
+				{Key: "client", Operator: Exists},
+				{Key: "legacy", Operator: NotExists},
+			},
+		},
+		{in: "=prod", wantErr: true},
+		{in: "!=prod", wantErr: true},
+		{in: "!", wantErr: true},
+		{in: "env=prod,", wantErr: true},
+		{in: "a b=c", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tags := map[string]string{"env": "prod", "team": "web", "client": ""}
+
+	tests := []struct {
+		sel  string
+		tags map[string]string
+		want bool
+	}{
+		{sel: "", tags: tags, want: true},
+		{sel: "", tags: nil, want: true},
+		{sel: "env=prod", tags: tags, want: true},
+		{sel: "env=dev", tags: tags, want: false},
+		{sel: "env=prod", tags: nil, want: false},
+		{sel: "team!=data", tags: tags, want: true},
+		{sel: "team!=web", tags: tags, want: false},
+		{sel: "owner!=me", tags: tags, want: true},
+		{sel: "client", tags: tags, want: true},
+		{sel: "owner", tags: tags, want: false},
+		{sel: "!legacy", tags: tags, want: true},
+		{sel: "!client", tags: tags, want: false},
+		{sel: "client=", tags: tags, want: true},
+		{sel: "env=prod,team!=data,client,!legacy", tags: tags, want: true},
+		{sel: "env=prod,team=data", tags: tags, want: false},
+	}
+
+	for _, tt := range tests {
+		sel, err := Parse(tt.sel)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.sel, err)
+		}
+		if got := sel.Matches(tt.tags); got != tt.want {
+			t.Errorf("%q.Matches(%v) = %v, want %v", tt.sel, tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	for _, in := range []string{"env=prod", "team!=data", "client", "!legacy", "env=prod,team!=data,client,!legacy"} {
+		sel, err := Parse(in)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", in, err)
+		}
+		if got := sel.String(); got != in {
+			t.Errorf("Parse(%q).String() = %q", in, got)
+		}
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		in         string
+		key, value string
+		wantErr    bool
+	}{
+		{in: "env=prod", key: "env", value: "prod"},
+		{in: "client", key: "client", value: ""},
+		{in: " env = prod ", key: "env", value: "prod"},
+		{in: "=prod", wantErr: true},
+		{in: "env=a,b", wantErr: true},
+		{in: "!env=prod", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		key, value, err := ParseTag(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseTag(%q) = %q, %q, want error", tt.in, key, value)
+			}
+			continue
+		}
+		if err != nil || key != tt.key || value != tt.value {
+			t.Errorf("ParseTag(%q) = %q, %q, %v, want %q, %q", tt.in, key, value, err, tt.key, tt.value)
+		}
+	}
+}
+
+func TestFormatTags(t *testing.T) {
+	got := FormatTags(map[string]string{"team": "data", "env": "prod", "client": ""})
+	if want := "client,env=prod,team=data"; got != want {
+		t.Errorf("FormatTags = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// notify watches the directory holding path, since gcloud may replace the
+// file rather than write it in place, and signals create, rename, delete and
+// close-after-write events on the file itself
+func notify(ctx context.Context, path string) (<-chan struct{}, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify init: %w", err)
+	}
+
+	const mask = unix.IN_CREATE | unix.IN_MOVED_TO | unix.IN_CLOSE_WRITE |
+		unix.IN_DELETE | unix.IN_MOVED_FROM
+	if _, err := unix.InotifyAddWatch(fd, filepath.Dir(path), mask); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("inotify watch %s: %w", filepath.Dir(path), err)
+	}
+
+	// A non-blocking fd is handled by the runtime poller, so closing the
+	// file unblocks a pending Read
+	file := os.NewFile(uintptr(fd), "inotify")
+	go func() {
+		<-ctx.Done()
+		file.Close()
+	}()
+
+	name := filepath.Base(path)
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+
+		buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+		for {
+			n, err := file.Read(buf)
+			if err != nil {
+				return
+			}
+
+			for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+				event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+				start := offset + unix.SizeofInotifyEvent
+				offset = start + int(event.Len)
+
+				eventName := strings.TrimRight(string(buf[start:offset]), "\x00")
+				if eventName == name || event.Mask&unix.IN_Q_OVERFLOW != 0 {
+					signal(changes)
+				}
+			}
+		}
+	}()
+
+	return changes, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package watcher
+
+import (
+	"context"
+	"errors"
+)
+
+// notify is only implemented with inotify; other platforms poll
+func notify(ctx context.Context, path string) (<-chan struct{}, error) {
+	return nil, errors.New("file notifications not supported")
+}
@@ -1,6 +1,7 @@
 package watcher
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -8,79 +9,174 @@ import (
 	"github.com/k0wl0n/gctx/pkg/adc"
 )
 
-// WatchADC watches for ADC file changes with timeout
-func WatchADC(timeout time.Duration) error {
+const (
+	// debounceDelay is how long the file must stay quiet before a change is
+	// reported, so that a write in progress is never validated
+	debounceDelay = 200 * time.Millisecond
+
+	// pollInterval is used when file system notifications are unavailable
+	pollInterval = 500 * time.Millisecond
+)
+
+// FileState describes a version of the watched file
+type FileState struct {
+	ModTime time.Time
+	Size    int64
+}
+
+// Event reports a change of the watched ADC file. State is nil when the file
+// was removed; Err is set when the new content is not valid ADC.
+type Event struct {
+	Path  string
+	State *FileState
+	Err   error
+}
+
+// Watch reports changes to the ADC file at path until ctx is done, then
+// closes the returned channel. Changes are detected with inotify where
+// available and by polling otherwise, debounced and validated before being
+// sent.
+func Watch(ctx context.Context, path string) (<-chan Event, error) {
+	changes, err := notify(ctx, path)
+	if err != nil {
+		changes, err = poll(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	events := make(chan Event, 1)
+	go debounce(ctx, path, changes, events)
+	return events, nil
+}
+
+// WatchADC waits for the default ADC file to be written with valid
+// credentials and returns its new state. If ctx ends first, the current file
+// is accepted as long as it is valid: gcloud leaves an up-to-date file
+// untouched.
+func WatchADC(ctx context.Context, events <-chan Event) (*FileState, error) {
 	adcPath := adc.GetDefaultADCPath()
 
-	// Get initial state
-	initialState, err := getFileState(adcPath)
-	if err != nil && !os.IsNotExist(err) {
-		return err
+	if events != nil {
+	loop:
+		for {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					break loop
+				}
+				if event.State != nil && event.Err == nil {
+					return event.State, nil
+				}
+			case <-ctx.Done():
+				break loop
+			}
+		}
 	}
 
-	fmt.Println("Watching for ADC file changes...")
+	if err := adc.ValidateADC(adcPath); err != nil {
+		return nil, fmt.Errorf("timeout waiting for ADC file: %w", err)
+	}
+	return getFileState(adcPath)
+}
 
-	ticker := time.NewTicker(500 * time.Millisecond)
-	defer ticker.Stop()
+// debounce turns raw change notifications into validated events, once the
+// file has been quiet for debounceDelay
+func debounce(ctx context.Context, path string, changes <-chan struct{}, events chan<- Event) {
+	defer close(events)
 
-	timeoutChan := time.After(timeout)
+	timer := time.NewTimer(debounceDelay)
+	timer.Stop()
+	defer timer.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
-			currentState, err := getFileState(adcPath)
-			if err != nil {
-				continue
+		case <-ctx.Done():
+			return
+		case _, ok := <-changes:
+			if !ok {
+				return
+			}
+			timer.Reset(debounceDelay)
+		case <-timer.C:
+			event := Event{Path: path}
+			state, err := getFileState(path)
+			switch {
+			case os.IsNotExist(err):
+			case err != nil:
+				event.Err = err
+			default:
+				event.State = state
+				event.Err = adc.ValidateADC(path)
 			}
 
-			// Check if file changed
-			// If initialState is nil (file didn't exist), any existence is a change
-			// If initialState exists, check if ModTime is after
-			if (initialState == nil && currentState != nil) ||
-				(initialState != nil && currentState.ModTime.After(initialState.ModTime)) {
-				// Wait for write to complete
-				time.Sleep(1 * time.Second)
-
-				// Validate
-				if err := adc.ValidateADC(adcPath); err == nil {
-					return nil
-				}
-			} else if initialState != nil && currentState != nil && initialState.ModTime.Equal(currentState.ModTime) {
-				// If the file exists and hasn't changed, but we are in a context where we expect it to be valid
-				// (e.g. after a successful auth command), we can check validity.
-				// However, the watcher's job is specifically to wait for a *change* or *creation*.
-				// If the auth command didn't update the file (e.g. because credentials were already valid),
-				// the timestamp won't change.
-
-				// For now, let's keep the strict behavior: if no change detected, we timeout.
-				// But we can add a check: if the file is valid right now, maybe we can return early?
-				// But that defeats the purpose of "watching for new login".
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
 			}
+		}
+	}
+}
+
+// poll detects changes by comparing the file state every pollInterval
+func poll(ctx context.Context, path string) (<-chan struct{}, error) {
+	initialState, err := getFileState(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
 
-		case <-timeoutChan:
-			// Check one last time if the file is valid before returning timeout error
-			if err := adc.ValidateADC(adcPath); err == nil {
-				// If the file is valid, we can consider it a success even if we didn't detect a modification timestamp change.
-				// This handles cases where gcloud didn't update the file because it was already up-to-date.
-				return nil
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		last := initialState
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current, err := getFileState(path)
+				if err != nil && !os.IsNotExist(err) {
+					continue
+				}
+				if current.equal(last) {
+					continue
+				}
+				last = current
+				signal(changes)
 			}
-			return fmt.Errorf("timeout waiting for ADC file")
 		}
+	}()
+
+	return changes, nil
+}
+
+// signal records a pending change without blocking; pending changes coalesce
+func signal(changes chan<- struct{}) {
+	select {
+	case changes <- struct{}{}:
+	default:
 	}
 }
 
-type fileState struct {
-	ModTime time.Time
-	Size    int64
+func (s *FileState) equal(other *FileState) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return s.ModTime.Equal(other.ModTime) && s.Size == other.Size
 }
 
-func getFileState(path string) (*fileState, error) {
+func getFileState(path string) (*FileState, error) {
 	info, err := os.Stat(path)
 	if err != nil {
 		return nil, err
 	}
 
-	return &fileState{
+	return &FileState{
 		ModTime: info.ModTime(),
 		Size:    info.Size(),
 	}, nil